@@ -0,0 +1,84 @@
+package args
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidArgs is the error wrapped by all errors returned by validators of this package.
+var ErrInvalidArgs = errors.New("args: invalid arguments")
+
+// Exact returns a validator which accepts exactly n arguments.
+func Exact(n int) func([]string) error {
+	return func(args []string) error {
+		if len(args) != n {
+			return fmt.Errorf("%w: expected exactly %d argument(s) but got %d", ErrInvalidArgs, n, len(args))
+		}
+
+		return nil
+	}
+}
+
+// Min returns a validator which accepts at least n arguments.
+func Min(n int) func([]string) error {
+	return func(args []string) error {
+		if len(args) < n {
+			return fmt.Errorf("%w: expected at least %d argument(s) but got %d", ErrInvalidArgs, n, len(args))
+		}
+
+		return nil
+	}
+}
+
+// Max returns a validator which accepts at most n arguments.
+func Max(n int) func([]string) error {
+	return func(args []string) error {
+		if len(args) > n {
+			return fmt.Errorf("%w: expected at most %d argument(s) but got %d", ErrInvalidArgs, n, len(args))
+		}
+
+		return nil
+	}
+}
+
+// Range returns a validator which accepts between min and max arguments (inclusive).
+func Range(min, max int) func([]string) error {
+	return func(args []string) error {
+		if len(args) < min || len(args) > max {
+			return fmt.Errorf("%w: expected between %d and %d argument(s) but got %d", ErrInvalidArgs, min, max,
+				len(args))
+		}
+
+		return nil
+	}
+}
+
+// OnlyValid returns a validator which accepts only arguments found in valid.
+func OnlyValid(valid ...string) func([]string) error {
+	return func(args []string) error {
+		for _, arg := range args {
+			if !slices.Contains(valid, arg) {
+				return fmt.Errorf("%w: invalid argument '%s' (expected one of: %s)", ErrInvalidArgs, arg,
+					strings.Join(valid, ", "))
+			}
+		}
+
+		return nil
+	}
+}
+
+// And returns a validator which accepts arguments accepted by all of the given validators. Validators are invoked in
+// order, and the first error is returned.
+func And(validators ...func([]string) error) func([]string) error {
+	return func(args []string) error {
+		for _, validate := range validators {
+			if err := validate(args); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
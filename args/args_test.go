@@ -0,0 +1,62 @@
+package args
+
+import (
+	"testing"
+
+	"github.com/brandon1024/cmder/internal/tutil"
+)
+
+func TestExact(t *testing.T) {
+	validate := Exact(2)
+
+	tutil.Assert(t, tutil.NilErr(validate([]string{"a", "b"})))
+	tutil.Assert(t, tutil.IsErr(validate([]string{"a"}), ErrInvalidArgs))
+	tutil.Assert(t, tutil.IsErr(validate([]string{"a", "b", "c"}), ErrInvalidArgs))
+	tutil.Assert(t, tutil.Eq("args: invalid arguments: expected exactly 2 argument(s) but got 1",
+		validate([]string{"a"}).Error()))
+}
+
+func TestMin(t *testing.T) {
+	validate := Min(1)
+
+	tutil.Assert(t, tutil.NilErr(validate([]string{"a"})))
+	tutil.Assert(t, tutil.NilErr(validate([]string{"a", "b"})))
+	tutil.Assert(t, tutil.IsErr(validate(nil), ErrInvalidArgs))
+}
+
+func TestMax(t *testing.T) {
+	validate := Max(1)
+
+	tutil.Assert(t, tutil.NilErr(validate(nil)))
+	tutil.Assert(t, tutil.NilErr(validate([]string{"a"})))
+	tutil.Assert(t, tutil.IsErr(validate([]string{"a", "b"}), ErrInvalidArgs))
+}
+
+func TestRange(t *testing.T) {
+	validate := Range(1, 2)
+
+	tutil.Assert(t, tutil.IsErr(validate(nil), ErrInvalidArgs))
+	tutil.Assert(t, tutil.NilErr(validate([]string{"a"})))
+	tutil.Assert(t, tutil.NilErr(validate([]string{"a", "b"})))
+	tutil.Assert(t, tutil.IsErr(validate([]string{"a", "b", "c"}), ErrInvalidArgs))
+}
+
+func TestOnlyValid(t *testing.T) {
+	validate := OnlyValid("start", "stop")
+
+	tutil.Assert(t, tutil.NilErr(validate(nil)))
+	tutil.Assert(t, tutil.NilErr(validate([]string{"stop", "start"})))
+	tutil.Assert(t, tutil.Eq("args: invalid arguments: invalid argument 'restart' (expected one of: start, stop)",
+		validate([]string{"start", "restart"}).Error()))
+}
+
+func TestAnd(t *testing.T) {
+	validate := And(Min(1), Max(2), OnlyValid("start", "stop"))
+
+	tutil.Assert(t, tutil.NilErr(validate([]string{"start"})))
+	tutil.Assert(t, tutil.NilErr(validate([]string{"start", "stop"})))
+	tutil.Assert(t, tutil.IsErr(validate(nil), ErrInvalidArgs))
+	tutil.Assert(t, tutil.IsErr(validate([]string{"start", "stop", "start"}), ErrInvalidArgs))
+	tutil.Assert(t, tutil.IsErr(validate([]string{"restart"}), ErrInvalidArgs))
+	tutil.Assert(t, tutil.NilErr(And()(nil)))
+}
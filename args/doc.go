@@ -0,0 +1,19 @@
+/*
+Package args offers composable validators for the positional arguments given to commands.
+
+Each validator is a func([]string) error, returning a non-nil error if the arguments are invalid. Validators can be
+combined with [And]:
+
+	validate := args.And(args.Min(1), args.OnlyValid("start", "stop", "restart"))
+
+	func (c *ServiceCommand) Run(ctx context.Context, args []string) error {
+		if err := validate(args); err != nil {
+			return errors.Join(cmder.ErrShowUsage, err)
+		}
+
+		// ...
+	}
+
+All errors returned by validators wrap [ErrInvalidArgs].
+*/
+package args
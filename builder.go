@@ -0,0 +1,119 @@
+package cmder
+
+import (
+	"context"
+	"flag"
+)
+
+// CommandBuilder is a fluent builder for [BaseCommand] trees. For simple command trees, CommandBuilder can help reduce
+// boilerplate:
+//
+//	cmd := cmder.Tree(
+//		cmder.New("root").Sub(
+//			cmder.New("child").Run(fn),
+//		),
+//	)
+//
+// To initialize a CommandBuilder, see [New]. To assemble the command tree, see [Tree].
+type CommandBuilder struct {
+	cmd      BaseCommand
+	children []*CommandBuilder
+}
+
+// New initializes a new [CommandBuilder] for a command with the given name.
+func New(name string) *CommandBuilder {
+	return &CommandBuilder{
+		cmd: BaseCommand{CommandName: name},
+	}
+}
+
+// Usage sets the usage line of the command. See UsageLine() in [Documented].
+func (b *CommandBuilder) Usage(usage string) *CommandBuilder {
+	b.cmd.Usage = usage
+	return b
+}
+
+// ShortHelp sets the short help line of the command. See ShortHelpText() in [Documented].
+func (b *CommandBuilder) ShortHelp(help string) *CommandBuilder {
+	b.cmd.ShortHelp = help
+	return b
+}
+
+// Help sets the documentation of the command. See HelpText() in [Documented].
+func (b *CommandBuilder) Help(help string) *CommandBuilder {
+	b.cmd.Help = help
+	return b
+}
+
+// Examples sets usage examples for the command. See ExampleText() in [Documented].
+func (b *CommandBuilder) Examples(examples string) *CommandBuilder {
+	b.cmd.Examples = examples
+	return b
+}
+
+// Hidden marks the command as hidden in help and usage texts. See [HiddenCommand].
+func (b *CommandBuilder) Hidden() *CommandBuilder {
+	b.cmd.IsHidden = true
+	return b
+}
+
+// Aliases appends alternative names for the command. See [AliasedCommand].
+func (b *CommandBuilder) Aliases(aliases ...string) *CommandBuilder {
+	b.cmd.CommandAliases = append(b.cmd.CommandAliases, aliases...)
+	return b
+}
+
+// Flags sets the function used to register command flags. See [FlagInitializer].
+func (b *CommandBuilder) Flags(fn func(*flag.FlagSet)) *CommandBuilder {
+	b.cmd.InitFlagsFunc = fn
+	return b
+}
+
+// PersistentFlags sets the function used to register flags shared with all subcommands. See
+// [PersistentFlagInitializer].
+func (b *CommandBuilder) PersistentFlags(fn func(*flag.FlagSet)) *CommandBuilder {
+	b.cmd.InitPersistentFlagsFunc = fn
+	return b
+}
+
+// Check sets the precondition check of the command. See [Precondition].
+func (b *CommandBuilder) Check(fn func(context.Context) error) *CommandBuilder {
+	b.cmd.CheckFunc = fn
+	return b
+}
+
+// Init sets the initialization routine of the command. See [Initializer].
+func (b *CommandBuilder) Init(fn func(context.Context, []string) error) *CommandBuilder {
+	b.cmd.InitFunc = fn
+	return b
+}
+
+// Run sets the run routine of the command. See [Runnable].
+func (b *CommandBuilder) Run(fn func(context.Context, []string) error) *CommandBuilder {
+	b.cmd.RunFunc = fn
+	return b
+}
+
+// Destroy sets the teardown routine of the command. See [Destroyer].
+func (b *CommandBuilder) Destroy(fn func(context.Context, []string) error) *CommandBuilder {
+	b.cmd.DestroyFunc = fn
+	return b
+}
+
+// Sub appends one or more subcommands to the command. See [RootCommand].
+func (b *CommandBuilder) Sub(children ...*CommandBuilder) *CommandBuilder {
+	b.children = append(b.children, children...)
+	return b
+}
+
+// Tree assembles the [BaseCommand] tree described by root. Each call to Tree returns a new, independent command tree.
+func Tree(root *CommandBuilder) *BaseCommand {
+	cmd := root.cmd
+	cmd.Children = nil
+
+	for _, child := range root.children {
+		cmd.Children = append(cmd.Children, Tree(child))
+	}
+
+	return &cmd
+}
@@ -0,0 +1,59 @@
+package cmder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brandon1024/cmder/internal/tutil"
+)
+
+func TestTree(t *testing.T) {
+	t.Run("should build a two-level tree", func(t *testing.T) {
+		cmd := Tree(
+			New("root").Usage("root [command]").Sub(
+				New("child-1").ShortHelp("first child"),
+				New("child-2").ShortHelp("second child").Hidden(),
+			),
+		)
+
+		tutil.Assert(t, tutil.Eq("root", cmd.Name()))
+		tutil.Assert(t, tutil.Eq("root [command]", cmd.UsageLine()))
+		tutil.Assert(t, tutil.Eq(2, len(cmd.Subcommands())))
+
+		c1, c2 := cmd.Subcommands()[0], cmd.Subcommands()[1]
+		tutil.Assert(t, tutil.Eq("child-1", c1.Name()))
+		tutil.Assert(t, tutil.Eq("first child", c1.ShortHelpText()))
+		tutil.Assert(t, tutil.Eq("child-2", c2.Name()))
+		tutil.Assert(t, tutil.Eq(true, c2.(HiddenCommand).Hidden()))
+	})
+
+	t.Run("should execute leaf command", func(t *testing.T) {
+		var (
+			lifecycle []string
+			result    []string
+		)
+
+		record := func(s string) func(context.Context, []string) error {
+			return func(ctx context.Context, args []string) error {
+				lifecycle = append(lifecycle, s)
+				return nil
+			}
+		}
+
+		cmd := Tree(
+			New("root").Init(record("root-init")).Destroy(record("root-destroy")).Sub(
+				New("child").Init(record("child-init")).Destroy(record("child-destroy")).Run(
+					func(ctx context.Context, args []string) error {
+						result = args
+						return nil
+					},
+				),
+			),
+		)
+
+		err := Execute(t.Context(), cmd, WithArgs([]string{"child", "a", "b"}))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Match([]string{"a", "b"}, result))
+		tutil.Assert(t, tutil.Match([]string{"root-init", "child-init", "child-destroy", "root-destroy"}, lifecycle))
+	})
+}
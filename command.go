@@ -50,6 +50,18 @@ type Initializer interface {
 	Initialize(context.Context, []string) error
 }
 
+// Precondition may be implemented by commands that need to verify preconditions (e.g. required environment,
+// connectivity or operating system) before executing. Keeping such checks out of the [Initializer] Initialize() routine
+// lets commands fail fast with a clear error.
+//
+// See [Execute] for more details on the lifecycle of command execution.
+type Precondition interface {
+	// Check verifies that the preconditions of this [Command] are met. Check is invoked before Initialize() of the
+	// command. Errors returned by Check will abort execution of the command lifecycle (Initialize()/Run()/Destroy() of
+	// this command and Run()/Destroy() of parent command(s)).
+	Check(context.Context) error
+}
+
 // Destroyer may be implemented by commands that need to do some work after the [Runnable] Run() routine is invoked.
 //
 // See [Execute] for more details on the lifecycle of command execution.
@@ -113,12 +125,51 @@ type HiddenCommand interface {
 	Hidden() bool
 }
 
+// AliasedCommand is implemented by commands which may also be invoked by alternative names. For example, a command
+// named 'generate' may also be invoked as 'gen' or 'g'. Aliases are matched when dispatching subcommands but are not
+// rendered in command listings.
+//
+// [Execute] returns [ErrIllegalCommandConfiguration] if an alias collides with the name or alias of a sibling command.
+type AliasedCommand interface {
+	// Aliases returns alternative names for this command.
+	Aliases() []string
+}
+
+// TemplatedCommand is implemented by commands which render their usage with a custom template, instead of the template
+// given to [Execute] (see [WithUsageTemplate]). This allows complex commands to opt into a richer usage text (e.g.
+// [LongHelpTemplate]) while other commands keep the default.
+type TemplatedCommand interface {
+	// UsageTemplate returns the text template for rendering usage information for this command.
+	UsageTemplate() string
+}
+
+// VersionedCommand is implemented by commands which report their own version. This is useful for tools where
+// subcommands are versioned separately (e.g. plugins).
+//
+// If the command does not define a '--version' flag, one is registered automatically and will instruct [Execute] to
+// render the command version.
+type VersionedCommand interface {
+	// Version returns the version of this command.
+	Version() string
+}
+
+// EnvPrefixCommand is implemented by commands which bind their flags to environment variables in their own namespace
+// (see [WithEnvironmentBinding]). The prefix replaces the prefix given to [Execute] and the command path, so that a
+// flag 'bucket' of a command with prefix 'STORAGE_' is bound to variable STORAGE_BUCKET, regardless of where the
+// command sits in the command tree. Subcommands are unaffected.
+type EnvPrefixCommand interface {
+	// EnvPrefix returns the prefix of environment variables bound to the flags of this command.
+	EnvPrefix() string
+}
+
 // Compile-time checks.
 var (
 	_ Command         = &BaseCommand{}
 	_ Initializer     = &BaseCommand{}
+	_ Precondition    = &BaseCommand{}
 	_ Destroyer       = &BaseCommand{}
 	_ RootCommand     = &BaseCommand{}
+	_ AliasedCommand  = &BaseCommand{}
 	_ FlagInitializer = &BaseCommand{}
 	_ Documented      = &CommandDocumentation{}
 	_ HiddenCommand   = &CommandDocumentation{}
@@ -177,17 +228,27 @@ func (d CommandDocumentation) Hidden() bool {
 	return d.IsHidden
 }
 
-// BaseCommand is an implementation of the [Command], [Initializer], [Destroyer], [RootCommand] and [FlagInitializer]
-// interfaces and may be embedded in your command types to reduce boilerplate.
+// BaseCommand is an implementation of the [Command], [Precondition], [Initializer], [Destroyer], [RootCommand],
+// [AliasedCommand], [FlagInitializer] and [PersistentFlagInitializer] interfaces and may be embedded in your command
+// types to reduce boilerplate.
 type BaseCommand struct {
 	CommandDocumentation
 
 	// The command name. See Name() in [Command].
 	CommandName string
 
+	// Alternative names for the command. See Aliases() in [AliasedCommand].
+	CommandAliases []string
+
 	// Optional function invoked by the default InitializeFlags() function.
 	InitFlagsFunc func(*flag.FlagSet)
 
+	// Optional function invoked by the default InitializePersistentFlags() function.
+	InitPersistentFlagsFunc func(*flag.FlagSet)
+
+	// Optional function invoked by the default Check() function.
+	CheckFunc func(context.Context) error
+
 	// Optional function invoked by the default Initialize() function.
 	InitFunc func(context.Context, []string) error
 
@@ -208,6 +269,13 @@ func (c BaseCommand) Name() string {
 	return c.CommandName
 }
 
+// Aliases returns [BaseCommand] CommandAliases.
+//
+// See [AliasedCommand].
+func (c BaseCommand) Aliases() []string {
+	return c.CommandAliases
+}
+
 // InitializeFlags runs [BaseCommand] InitFlagsFunc, if not nil.
 //
 // See [FlagInitializer].
@@ -217,6 +285,26 @@ func (c BaseCommand) InitializeFlags(fs *flag.FlagSet) {
 	}
 }
 
+// InitializePersistentFlags runs [BaseCommand] InitPersistentFlagsFunc, if not nil.
+//
+// See [PersistentFlagInitializer].
+func (c BaseCommand) InitializePersistentFlags(fs *flag.FlagSet) {
+	if c.InitPersistentFlagsFunc != nil {
+		c.InitPersistentFlagsFunc(fs)
+	}
+}
+
+// Check runs [BaseCommand] CheckFunc, if not nil.
+//
+// See [Precondition].
+func (c BaseCommand) Check(ctx context.Context) error {
+	if c.CheckFunc != nil {
+		return c.CheckFunc(ctx)
+	}
+
+	return nil
+}
+
 // Initialize runs [BaseCommand] InitFunc, if not nil.
 //
 // See [Initializer].
@@ -1,5 +1,14 @@
 package cmder
 
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/brandon1024/cmder/internal/levenshtein"
+)
+
 // collectSubcommands collects the immediate subcommands of the given [Command] into a map keyed by the command
 // [Command] Name(). Returns an empty map if the command is not a [RootCommand].
 func collectSubcommands(cmd Command) map[string]Command {
@@ -16,3 +25,62 @@ func collectSubcommands(cmd Command) map[string]Command {
 
 	return subcommands
 }
+
+// dispatchSubcommands collects the immediate subcommands of the given [Command] into a map keyed by the command
+// [Command] Name() and any [AliasedCommand] Aliases(). Returns an error if an alias collides with the name or alias of
+// another subcommand.
+func dispatchSubcommands(cmd Command) (map[string]Command, error) {
+	subcommands := collectSubcommands(cmd)
+
+	c, ok := cmd.(RootCommand)
+	if !ok {
+		return subcommands, nil
+	}
+
+	owners := map[string]string{}
+	for name := range subcommands {
+		owners[name] = name
+	}
+
+	for _, subcommand := range c.Subcommands() {
+		aliased, ok := subcommand.(AliasedCommand)
+		if !ok {
+			continue
+		}
+
+		for _, alias := range aliased.Aliases() {
+			if owner, ok := owners[alias]; ok && owner != subcommand.Name() {
+				return nil, errors.Join(ErrIllegalCommandConfiguration,
+					fmt.Errorf("cmder: alias '%s' of command '%s' collides with command '%s'", alias,
+						subcommand.Name(), owner))
+			}
+
+			owners[alias] = subcommand.Name()
+			subcommands[alias] = subcommand
+		}
+	}
+
+	return subcommands, nil
+}
+
+// suggestSubcommand returns the name of the visible subcommand most similar to name, or an empty string if no
+// subcommand is similar enough. Only small edit distances (at most two, and less than half the length of the
+// subcommand name) are considered.
+func suggestSubcommand(subcommands map[string]Command, name string) string {
+	var (
+		suggestion string
+		best       = 3
+	)
+
+	for _, candidate := range slices.Sorted(maps.Keys(subcommands)) {
+		if hidden, ok := subcommands[candidate].(HiddenCommand); ok && hidden.Hidden() {
+			continue
+		}
+
+		if d := levenshtein.Distance(name, candidate); d < best && d*2 < len(candidate) {
+			suggestion, best = candidate, d
+		}
+	}
+
+	return suggestion
+}
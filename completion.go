@@ -0,0 +1,471 @@
+package cmder
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/brandon1024/cmder/getopt"
+)
+
+// completionCommandName is the name of the subcommand injected by [WithCompletionCommand].
+const completionCommandName = "completion"
+
+// completionCommand is the [Command] injected by [WithCompletionCommand]. It renders a shell completion script for the
+// root command.
+type completionCommand struct {
+	CommandDocumentation
+
+	root Command
+	ops  *ExecuteOptions
+}
+
+// newCompletionCommand builds the completion subcommand for root. Completion scripts are written to the output writer
+// of ops, and complete the command tree as executed with ops.
+func newCompletionCommand(root Command, ops *ExecuteOptions) *completionCommand {
+	return &completionCommand{
+		CommandDocumentation: CommandDocumentation{
+			Usage:     fmt.Sprintf("%s completion bash|zsh|fish", root.Name()),
+			ShortHelp: "generate shell completion scripts",
+			Help: fmt.Sprintf(`Generate a completion script for %[1]s for the given shell. Load completions in your current shell
+session with:
+
+  source <(%[1]s completion bash)
+  source <(%[1]s completion zsh)
+  %[1]s completion fish | source`, root.Name()),
+			IsHidden: true,
+		},
+		root: root,
+		ops:  ops,
+	}
+}
+
+// Name returns the name of the completion subcommand.
+func (c *completionCommand) Name() string {
+	return completionCommandName
+}
+
+// Run renders the completion script for the shell given in args.
+func (c *completionCommand) Run(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return ErrShowUsage
+	}
+
+	switch args[0] {
+	case "bash":
+		return genBashCompletion(c.root, c.ops.outputWriter, c.ops)
+	case "zsh":
+		return genZshCompletion(c.root, c.ops.outputWriter, c.ops)
+	case "fish":
+		return genFishCompletion(c.root, c.ops.outputWriter, c.ops)
+	default:
+		return errors.Join(ErrShowUsage, fmt.Errorf("cmder: unsupported shell '%s'", args[0]))
+	}
+}
+
+// completionNode describes the completion candidates of a single command in a command tree.
+type completionNode struct {
+	// the sequence of command names from the root command to this command (inclusive)
+	path []string
+
+	// names of visible subcommands
+	commands []string
+
+	// visible flags (including aliases), as given at the command line (e.g. '-a', '--all')
+	flags []string
+
+	// flags (including hidden flags and aliases) which accept an argument, as given at the command line
+	valueFlags []string
+
+	// descriptions of subcommands and flags, keyed by subcommand name or flag (e.g. '--all')
+	descriptions map[string]string
+}
+
+// completionTree walks the command tree rooted at cmd and returns a completion node for every visible command. Hidden
+// commands (see [HiddenCommand]) and hidden flags (see [getopt.Hide]) are omitted. Commands are initialized as done by
+// [Execute] with ops.
+func completionTree(cmd Command, ops *ExecuteOptions) []completionNode {
+	var walk func(cmd Command, parent *command, path []string) []completionNode
+
+	walk = func(cmd Command, parent *command, path []string) []completionNode {
+		path = append(slices.Clone(path), cmd.Name())
+
+		node := completionNode{path: path, descriptions: map[string]string{}}
+
+		// commands with colliding persistent flags cannot be executed, so they are not completed either
+		this, err := newCommand(cmd, parent, path, ops)
+		if err != nil {
+			return nil
+		}
+
+		this.fs.VisitAll(func(flg *flag.Flag) {
+			if !getopt.IsHidden(flg) {
+				_, usage := flag.UnquoteUsage(flg)
+
+				node.flags = append(node.flags, completionFlag(flg.Name))
+				node.descriptions[completionFlag(flg.Name)] = usage
+			}
+			if !isBoolFlag(flg.Value) {
+				node.valueFlags = append(node.valueFlags, completionFlag(flg.Name))
+			}
+		})
+
+		nodes := []completionNode{node}
+
+		subcommands := collectSubcommands(cmd)
+		for _, name := range slices.Sorted(maps.Keys(subcommands)) {
+			if hidden, ok := subcommands[name].(HiddenCommand); ok && hidden.Hidden() {
+				continue
+			}
+
+			children := walk(subcommands[name], this, path)
+			if children == nil {
+				continue
+			}
+
+			nodes[0].commands = append(nodes[0].commands, name)
+			nodes[0].descriptions[name] = subcommands[name].ShortHelpText()
+			nodes = append(nodes, children...)
+		}
+
+		return nodes
+	}
+
+	return walk(cmd, nil, nil)
+}
+
+// completionFlag returns the flag with the given name as given at the command line.
+func completionFlag(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+
+	return "--" + name
+}
+
+// isBoolFlag checks if v (or any value wrapped by v) is a boolean flag, which doesn't accept an argument.
+func isBoolFlag(v flag.Value) bool {
+	for v != nil {
+		if bf, ok := v.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+			return true
+		}
+
+		w, ok := v.(interface{ Unwrap() flag.Value })
+		if !ok {
+			return false
+		}
+
+		v = w.Unwrap()
+	}
+
+	return false
+}
+
+// completionFunc returns a shell function name for the command with the given name.
+func completionFunc(name string) string {
+	return "_" + regexp.MustCompile("[^a-zA-Z0-9_]+").ReplaceAllString(name, "_") + "_completions"
+}
+
+// GenBashCompletion writes a bash completion script for cmd to w. The script completes subcommand names and flags
+// (including aliases) at every level of the command tree rooted at cmd, and falls back to file completion for flag
+// values and arguments. Hidden commands (see [HiddenCommand]) and hidden flags (see [getopt.Hide]) are not completed.
+//
+// Flags are completed with getopt syntax ('-a', '--all'). Flags of each command are initialized as done by [Execute]
+// with the given options (see [FlagInitializer]). For instance, help flags are not completed with [WithNoHelpFlags].
+//
+// Load the script in bash with:
+//
+//	source <(mytool completion bash)
+//
+// See also [WithCompletionCommand].
+func GenBashCompletion(cmd Command, w io.Writer, op ...ExecuteOption) error {
+	return genBashCompletion(cmd, w, newExecuteOptions(op...))
+}
+
+// genBashCompletion writes a bash completion script for cmd to w, initializing commands with ops.
+func genBashCompletion(cmd Command, w io.Writer, ops *ExecuteOptions) error {
+	var (
+		nodes    = completionTree(cmd, ops)
+		fn       = completionFunc(cmd.Name())
+		commands []string
+		values   []string
+		b        strings.Builder
+	)
+
+	for _, node := range nodes {
+		for _, flg := range node.valueFlags {
+			values = append(values, shellQuote(strings.Join(node.path, " ")+" "+flg))
+		}
+
+		if len(node.path) > 1 {
+			commands = append(commands, shellQuote(strings.Join(node.path, " ")))
+		}
+	}
+
+	fmt.Fprintf(&b, "# bash completion for %s\n\n", cmd.Name())
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	fmt.Fprintf(&b, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	fmt.Fprintf(&b, "    local path=%s word i skip=0\n\n", shellQuote(cmd.Name()))
+	fmt.Fprintf(&b, "    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	fmt.Fprintf(&b, "        word=\"${COMP_WORDS[i]}\"\n")
+	fmt.Fprintf(&b, "        if [[ \"${skip}\" == 1 ]]; then\n")
+	fmt.Fprintf(&b, "            skip=0\n")
+	fmt.Fprintf(&b, "            continue\n")
+	fmt.Fprintf(&b, "        fi\n\n")
+	fmt.Fprintf(&b, "        case \"${path} ${word}\" in\n")
+	if len(commands) > 0 {
+		fmt.Fprintf(&b, "            %s) path=\"${path} ${word}\" ;;\n", strings.Join(commands, "|"))
+	}
+	if len(values) > 0 {
+		fmt.Fprintf(&b, "            %s) skip=1 ;;\n", strings.Join(values, "|"))
+	}
+	fmt.Fprintf(&b, "        esac\n")
+	fmt.Fprintf(&b, "    done\n\n")
+	if len(values) > 0 {
+		fmt.Fprintf(&b, "    # complete flag arguments with the default completion\n")
+		fmt.Fprintf(&b, "    case \"${path} ${prev}\" in\n")
+		fmt.Fprintf(&b, "        %s) return ;;\n", strings.Join(values, "|"))
+		fmt.Fprintf(&b, "    esac\n\n")
+	}
+	fmt.Fprintf(&b, "    local commands=\"\" flags=\"\"\n")
+	fmt.Fprintf(&b, "    case \"${path}\" in\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "        %s)\n", shellQuote(strings.Join(node.path, " ")))
+		fmt.Fprintf(&b, "            commands=%s\n", shellQuote(strings.Join(node.commands, " ")))
+		fmt.Fprintf(&b, "            flags=%s\n", shellQuote(strings.Join(node.flags, " ")))
+		fmt.Fprintf(&b, "            ;;\n")
+	}
+	fmt.Fprintf(&b, "    esac\n\n")
+	fmt.Fprintf(&b, "    if [[ \"${cur}\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"${flags}\" -- \"${cur}\"))\n")
+	fmt.Fprintf(&b, "    elif [[ -n \"${commands}\" ]]; then\n")
+	fmt.Fprintf(&b, "        COMPREPLY=($(compgen -W \"${commands}\" -- \"${cur}\"))\n")
+	fmt.Fprintf(&b, "    fi\n")
+	fmt.Fprintf(&b, "}\n\n")
+	fmt.Fprintf(&b, "complete -o default -F %s %s\n", fn, shellQuote(cmd.Name()))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// GenZshCompletion writes a zsh completion script for cmd to w. Like [GenBashCompletion], the script completes
+// subcommand names and flags (including aliases) at every level of the command tree rooted at cmd, omitting hidden
+// commands and flags. Subcommands are described by their short help text (see [Documented]) and flags by their usage.
+//
+// Load the script in zsh with:
+//
+//	source <(mytool completion zsh)
+//
+// Alternatively, write the script to a file named '_mytool' in a directory of your fpath.
+//
+// See also [WithCompletionCommand].
+func GenZshCompletion(cmd Command, w io.Writer, op ...ExecuteOption) error {
+	return genZshCompletion(cmd, w, newExecuteOptions(op...))
+}
+
+// genZshCompletion writes a zsh completion script for cmd to w, initializing commands with ops.
+func genZshCompletion(cmd Command, w io.Writer, ops *ExecuteOptions) error {
+	var (
+		nodes    = completionTree(cmd, ops)
+		fn       = strings.TrimSuffix(completionFunc(cmd.Name()), "_completions")
+		commands []string
+		values   []string
+		b        strings.Builder
+	)
+
+	for _, node := range nodes {
+		for _, flg := range node.valueFlags {
+			values = append(values, shellQuote(strings.Join(node.path, " ")+" "+flg))
+		}
+
+		if len(node.path) > 1 {
+			commands = append(commands, shellQuote(strings.Join(node.path, " ")))
+		}
+	}
+
+	fmt.Fprintf(&b, "#compdef %s\n\n", cmd.Name())
+	fmt.Fprintf(&b, "# zsh completion for %s\n\n", cmd.Name())
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	fmt.Fprintf(&b, "    local cmd_path=%s word i skip=0\n\n", shellQuote(cmd.Name()))
+	fmt.Fprintf(&b, "    for ((i = 2; i < CURRENT; i++)); do\n")
+	fmt.Fprintf(&b, "        word=\"${words[i]}\"\n")
+	fmt.Fprintf(&b, "        if [[ \"${skip}\" == 1 ]]; then\n")
+	fmt.Fprintf(&b, "            skip=0\n")
+	fmt.Fprintf(&b, "            continue\n")
+	fmt.Fprintf(&b, "        fi\n\n")
+	fmt.Fprintf(&b, "        case \"${cmd_path} ${word}\" in\n")
+	if len(commands) > 0 {
+		fmt.Fprintf(&b, "            %s) cmd_path=\"${cmd_path} ${word}\" ;;\n", strings.Join(commands, "|"))
+	}
+	if len(values) > 0 {
+		fmt.Fprintf(&b, "            %s) skip=1 ;;\n", strings.Join(values, "|"))
+	}
+	fmt.Fprintf(&b, "        esac\n")
+	fmt.Fprintf(&b, "    done\n\n")
+	if len(values) > 0 {
+		fmt.Fprintf(&b, "    # complete flag arguments with files\n")
+		fmt.Fprintf(&b, "    case \"${cmd_path} ${words[CURRENT-1]}\" in\n")
+		fmt.Fprintf(&b, "        %s)\n", strings.Join(values, "|"))
+		fmt.Fprintf(&b, "            _files\n")
+		fmt.Fprintf(&b, "            return\n")
+		fmt.Fprintf(&b, "            ;;\n")
+		fmt.Fprintf(&b, "    esac\n\n")
+	}
+	fmt.Fprintf(&b, "    local -a commands flags\n")
+	fmt.Fprintf(&b, "    case \"${cmd_path}\" in\n")
+	for _, node := range nodes {
+		fmt.Fprintf(&b, "        %s)\n", shellQuote(strings.Join(node.path, " ")))
+		fmt.Fprintf(&b, "            commands=(%s)\n", zshDescribe(node.commands, node.descriptions))
+		fmt.Fprintf(&b, "            flags=(%s)\n", zshDescribe(node.flags, node.descriptions))
+		fmt.Fprintf(&b, "            ;;\n")
+	}
+	fmt.Fprintf(&b, "    esac\n\n")
+	fmt.Fprintf(&b, "    if [[ \"${words[CURRENT]}\" == -* ]]; then\n")
+	fmt.Fprintf(&b, "        _describe -t flags 'flag' flags\n")
+	fmt.Fprintf(&b, "    elif (( ${#commands} )); then\n")
+	fmt.Fprintf(&b, "        _describe -t commands 'command' commands\n")
+	fmt.Fprintf(&b, "    else\n")
+	fmt.Fprintf(&b, "        _files\n")
+	fmt.Fprintf(&b, "    fi\n")
+	fmt.Fprintf(&b, "}\n\n")
+	fmt.Fprintf(&b, "if [[ \"${funcstack[1]}\" == %s ]]; then\n", shellQuote(fn))
+	fmt.Fprintf(&b, "    %s \"$@\"\n", fn)
+	fmt.Fprintf(&b, "else\n")
+	fmt.Fprintf(&b, "    compdef %s %s\n", fn, shellQuote(cmd.Name()))
+	fmt.Fprintf(&b, "fi\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// zshDescribe formats names and their descriptions as single-quoted 'name:description' words, as expected by the zsh
+// _describe completion function.
+func zshDescribe(names []string, descriptions map[string]string) string {
+	escape := strings.NewReplacer(":", `\:`)
+
+	var words []string
+
+	for _, name := range names {
+		word := escape.Replace(name)
+		if description, _, _ := strings.Cut(strings.TrimSpace(descriptions[name]), "\n"); description != "" {
+			word += ":" + description
+		}
+
+		words = append(words, shellQuote(word))
+	}
+
+	return strings.Join(words, " ")
+}
+
+// GenFishCompletion writes a fish completion script for cmd to w. Like [GenBashCompletion], the script completes
+// subcommand names and flags (including aliases) at every level of the command tree rooted at cmd, omitting hidden
+// commands and flags. Subcommands are described by their short help text (see [Documented]) and flags by their usage.
+// Flags which accept an argument are completed with files.
+//
+// Load the script in fish with:
+//
+//	mytool completion fish | source
+//
+// Alternatively, write the script to a file named 'mytool.fish' in a directory of your fish_complete_path.
+//
+// See also [WithCompletionCommand].
+func GenFishCompletion(cmd Command, w io.Writer, op ...ExecuteOption) error {
+	return genFishCompletion(cmd, w, newExecuteOptions(op...))
+}
+
+// genFishCompletion writes a fish completion script for cmd to w, initializing commands with ops.
+func genFishCompletion(cmd Command, w io.Writer, ops *ExecuteOptions) error {
+	var (
+		nodes  = completionTree(cmd, ops)
+		fn     = completionFunc(cmd.Name()) + "_path"
+		paths  []string
+		values []string
+		b      strings.Builder
+	)
+
+	for _, node := range nodes {
+		for _, flg := range node.valueFlags {
+			values = append(values, fishQuote(strings.Join(node.path, " ")+" "+flg))
+		}
+
+		if len(node.path) > 1 {
+			paths = append(paths, fishQuote(strings.Join(node.path, " ")))
+		}
+	}
+
+	fmt.Fprintf(&b, "# fish completion for %s\n\n", cmd.Name())
+	fmt.Fprintf(&b, "function %s\n", fn)
+	fmt.Fprintf(&b, "    set -l path %s\n", fishQuote(cmd.Name()))
+	fmt.Fprintf(&b, "    set -l skip 0\n")
+	fmt.Fprintf(&b, "    for word in (commandline -opc)[2..-1]\n")
+	fmt.Fprintf(&b, "        if test $skip = 1\n")
+	fmt.Fprintf(&b, "            set skip 0\n")
+	fmt.Fprintf(&b, "            continue\n")
+	fmt.Fprintf(&b, "        end\n\n")
+	fmt.Fprintf(&b, "        switch \"$path $word\"\n")
+	if len(paths) > 0 {
+		fmt.Fprintf(&b, "            case %s\n", strings.Join(paths, " "))
+		fmt.Fprintf(&b, "                set path \"$path $word\"\n")
+	}
+	if len(values) > 0 {
+		fmt.Fprintf(&b, "            case %s\n", strings.Join(values, " "))
+		fmt.Fprintf(&b, "                set skip 1\n")
+	}
+	fmt.Fprintf(&b, "        end\n")
+	fmt.Fprintf(&b, "    end\n")
+	fmt.Fprintf(&b, "    echo $path\n")
+	fmt.Fprintf(&b, "end\n\n")
+
+	for _, node := range nodes {
+		cond := fishQuote("test (" + fn + ") = " + fishQuote(strings.Join(node.path, " ")))
+
+		for _, name := range node.commands {
+			fmt.Fprintf(&b, "complete -c %s -f -n %s -a %s%s\n", fishQuote(cmd.Name()), cond, fishQuote(name),
+				fishDescribe(node.descriptions[name]))
+		}
+
+		for _, flg := range node.flags {
+			opt := "-s " + strings.TrimPrefix(flg, "-")
+			if long, ok := strings.CutPrefix(flg, "--"); ok {
+				opt = "-l " + long
+			}
+
+			if slices.Contains(node.valueFlags, flg) {
+				opt += " -r"
+			}
+
+			fmt.Fprintf(&b, "complete -c %s -n %s %s%s\n", fishQuote(cmd.Name()), cond, opt,
+				fishDescribe(node.descriptions[flg]))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// fishDescribe formats the first line of description as a fish complete '-d' option, or returns an empty string if
+// the description is empty.
+func fishDescribe(description string) string {
+	description, _, _ = strings.Cut(strings.TrimSpace(description), "\n")
+	if description == "" {
+		return ""
+	}
+
+	return " -d " + fishQuote(description)
+}
+
+// fishQuote quotes s as a single-quoted fish string.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// shellQuote quotes s as a single-quoted bash or zsh string.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
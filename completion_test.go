@@ -0,0 +1,298 @@
+package cmder
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/brandon1024/cmder/getopt"
+	"github.com/brandon1024/cmder/internal/tutil"
+)
+
+func TestCompletionCommand(t *testing.T) {
+	tree := func() Command {
+		return Tree(
+			New("root").Flags(func(fs *flag.FlagSet) {
+				fs.String("output", "-", "output file")
+			}).Sub(
+				New("remote").Sub(
+					New("add"),
+					New("remove").Flags(func(fs *flag.FlagSet) {
+						fs.Bool("f", false, "force")
+					}),
+				),
+				New("debug").Hidden(),
+			),
+		)
+	}
+
+	t.Run("should render bash completion script", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithCompletionCommand(), WithArgs([]string{"completion", "bash"}),
+			WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.NilErr(err))
+
+		script := buf.String()
+		tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(script, "# bash completion for root\n")))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(script, `commands='remote'`)))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(script, `commands='add remove'`)))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(script, `flags='-h --help --output'`)))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(script, `flags='-f -h --help'`)))
+		tutil.Assert(t, tutil.Eq(true, strings.HasSuffix(script, "complete -o default -F _root_completions 'root'\n")))
+		tutil.Assert(t, tutil.Eq(false, strings.Contains(script, "debug")))
+	})
+
+	t.Run("should render zsh and fish completion scripts", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithCompletionCommand(), WithArgs([]string{"completion", "zsh"}),
+			WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(buf.String(), "#compdef root\n")))
+
+		buf.Reset()
+
+		err = Execute(t.Context(), tree(), WithCompletionCommand(), WithArgs([]string{"completion", "fish"}),
+			WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(),
+			`complete -c 'root' -n 'test (_root_completions_path) = \'root remote remove\'' -s f`)))
+	})
+
+	t.Run("should render completion scripts with execute options", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithCompletionCommand(), WithNoHelpFlags(),
+			WithArgs([]string{"completion", "bash"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.NilErr(err))
+
+		script := buf.String()
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(script, `flags='--output'`)))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(script, `flags='-f'`)))
+		tutil.Assert(t, tutil.Eq(false, strings.Contains(script, "--help")))
+	})
+
+	t.Run("should return error for unsupported shell", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithCompletionCommand(), WithArgs([]string{"completion", "tcsh"}),
+			WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(err.Error(), "unsupported shell 'tcsh'")))
+	})
+
+	t.Run("should not inject completion command unless enabled", func(t *testing.T) {
+		var args []string
+
+		cmd := Tree(New("root").Run(func(_ context.Context, a []string) error {
+			args = a
+			return nil
+		}))
+
+		err := Execute(t.Context(), cmd, WithArgs([]string{"completion", "bash"}))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Match([]string{"completion", "bash"}, args))
+	})
+
+	t.Run("should not override existing completion command", func(t *testing.T) {
+		var ran bool
+
+		cmd := Tree(New("root").Sub(New("completion").Run(func(context.Context, []string) error {
+			ran = true
+			return nil
+		})))
+
+		err := Execute(t.Context(), cmd, WithCompletionCommand(), WithArgs([]string{"completion", "bash"}))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, ran))
+	})
+}
+
+func TestGenBashCompletion(t *testing.T) {
+	cmd := Tree(
+		New("tool").Flags(func(fs *flag.FlagSet) {
+			fs.String("output", "-", "output file")
+			getopt.Alias(fs, "output", "o")
+			fs.Bool("v", false, "verbose")
+			fs.Bool("debug", false, "debug")
+			getopt.Hide(fs, "debug")
+		}).Sub(
+			New("remote").Sub(New("add"), New("internal").Hidden()),
+			New("hidden").Hidden(),
+		),
+	)
+
+	var buf bytes.Buffer
+
+	err := GenBashCompletion(cmd, &buf)
+	tutil.Assert(t, tutil.NilErr(err))
+
+	script := buf.String()
+
+	expected := []string{
+		`'tool remote'|'tool remote add') path="${path} ${word}" ;;`,
+		`'tool -o'|'tool --output') skip=1 ;;`,
+		`commands='remote'`,
+		`flags='-h --help -o --output -v'`,
+		`commands='add'`,
+		"complete -o default -F _tool_completions 'tool'\n",
+	}
+
+	for _, e := range expected {
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(script, e)))
+	}
+
+	tutil.Assert(t, tutil.Eq(false, strings.Contains(script, "hidden")))
+	tutil.Assert(t, tutil.Eq(false, strings.Contains(script, "internal")))
+	tutil.Assert(t, tutil.Eq(false, strings.Contains(script, "--debug")))
+
+	buf.Reset()
+
+	err = GenBashCompletion(cmd, &buf, WithNoHelpFlags())
+	tutil.Assert(t, tutil.NilErr(err))
+	tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), `flags='-o --output -v'`)))
+}
+
+func TestGenZshCompletion(t *testing.T) {
+	cmd := Tree(
+		New("tool").Flags(func(fs *flag.FlagSet) {
+			fs.String("output", "-", "output `file`")
+			getopt.Alias(fs, "output", "o")
+			fs.Bool("debug", false, "debug")
+			getopt.Hide(fs, "debug")
+		}).Sub(
+			New("remote").ShortHelp("manage set of tracked repositories").Sub(
+				New("add").ShortHelp("add a remote named 'origin'"),
+			),
+			New("hidden").Hidden(),
+		),
+	)
+
+	var buf bytes.Buffer
+
+	err := GenZshCompletion(cmd, &buf)
+	tutil.Assert(t, tutil.NilErr(err))
+
+	script := buf.String()
+
+	expected := []string{
+		"#compdef tool\n",
+		`'tool remote'|'tool remote add') cmd_path="${cmd_path} ${word}" ;;`,
+		`'tool -o'|'tool --output') skip=1 ;;`,
+		`commands=('remote:manage set of tracked repositories')`,
+		`flags=('-h:show command usage information' '--help:show command help information' '-o:output file' ` +
+			`'--output:output file')`,
+		`commands=('add:add a remote named '\''origin'\''')`,
+		"compdef _tool 'tool'\n",
+	}
+
+	for _, e := range expected {
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(script, e)))
+	}
+
+	tutil.Assert(t, tutil.Eq(false, strings.Contains(script, "hidden")))
+	tutil.Assert(t, tutil.Eq(false, strings.Contains(script, "--debug")))
+}
+
+func TestGenFishCompletion(t *testing.T) {
+	cmd := Tree(
+		New("tool").Flags(func(fs *flag.FlagSet) {
+			fs.String("output", "-", "output `file`")
+			getopt.Alias(fs, "output", "o")
+			fs.Bool("debug", false, "debug")
+			getopt.Hide(fs, "debug")
+		}).Sub(
+			New("remote").ShortHelp("manage set of tracked repositories").Sub(
+				New("add").ShortHelp("add a remote named 'origin'"),
+			),
+			New("hidden").Hidden(),
+		),
+	)
+
+	var buf bytes.Buffer
+
+	err := GenFishCompletion(cmd, &buf)
+	tutil.Assert(t, tutil.NilErr(err))
+
+	script := buf.String()
+
+	expected := []string{
+		"# fish completion for tool\n",
+		`case 'tool remote' 'tool remote add'`,
+		`case 'tool -o' 'tool --output'` + "\n                set skip 1\n",
+		`complete -c 'tool' -f -n 'test (_tool_completions_path) = \'tool\'' -a 'remote' ` +
+			`-d 'manage set of tracked repositories'`,
+		`complete -c 'tool' -n 'test (_tool_completions_path) = \'tool\'' -s h -d 'show command usage information'`,
+		`complete -c 'tool' -n 'test (_tool_completions_path) = \'tool\'' -s o -r -d 'output file'`,
+		`complete -c 'tool' -n 'test (_tool_completions_path) = \'tool\'' -l output -r -d 'output file'`,
+		`complete -c 'tool' -f -n 'test (_tool_completions_path) = \'tool remote\'' -a 'add' ` +
+			`-d 'add a remote named \'origin\''`,
+	}
+
+	for _, e := range expected {
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(script, e)))
+	}
+
+	tutil.Assert(t, tutil.Eq(false, strings.Contains(script, "hidden")))
+	tutil.Assert(t, tutil.Eq(false, strings.Contains(script, "debug")))
+}
+
+func TestCompletionTree(t *testing.T) {
+	t.Run("should omit subcommands which cannot be initialized", func(t *testing.T) {
+		cmd := Tree(
+			New("tool").PersistentFlags(func(fs *flag.FlagSet) {
+				fs.Bool("verbose", false, "verbose")
+			}).Sub(
+				New("broken").Flags(func(fs *flag.FlagSet) {
+					fs.Bool("verbose", false, "verbose")
+				}),
+				New("ok"),
+			),
+		)
+
+		nodes := completionTree(cmd, &ExecuteOptions{})
+		tutil.Assert(t, tutil.Eq(2, len(nodes)))
+		tutil.Assert(t, tutil.Match([]string{"ok"}, nodes[0].commands))
+		tutil.Assert(t, tutil.Match([]string{"tool", "ok"}, nodes[1].path))
+	})
+}
+
+func TestCompletionQuoting(t *testing.T) {
+	cmd := Tree(
+		New("tool").Sub(
+			New(`it's`).Flags(func(fs *flag.FlagSet) {
+				fs.String("home", "", "home `dir`")
+			}),
+			New(`$HOME\n`),
+		),
+	)
+
+	t.Run("should single-quote bash and zsh words", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := GenBashCompletion(cmd, &buf)
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(),
+			`'tool $HOME\n'|'tool it'\''s') path="${path} ${word}" ;;`)))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), `'tool it'\''s --home') skip=1 ;;`)))
+
+		buf.Reset()
+
+		err = GenZshCompletion(cmd, &buf)
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), `        'tool it'\''s')`+"\n")))
+	})
+
+	t.Run("should single-quote fish words", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := GenFishCompletion(cmd, &buf)
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), `case 'tool $HOME\\n' 'tool it\'s'`)))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(),
+			`complete -c 'tool' -n 'test (_tool_completions_path) = \'tool it\\\'s\'' -l home -r`)))
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -104,8 +105,9 @@ func (c *ServerCommand) InitializeFlags(fs *flag.FlagSet) {
 		"Configures the maximum duration for writing a client response. Negative or zero (e.g. 0s) disables the timeout.")
 	fs.IntVar(&c.maxHeaderBytes, "http.max-header-size", http.DefaultMaxHeaderBytes,
 		"Set the maximum header size, in bytes. Negative or zero disables the limit.")
-	fs.Int64Var(&c.maxBodySize, "http.max-body-size", 1<<26,
-		"Set the maximum request body size, in bytes. Negative or zero disables the limit.")
+	c.maxBodySize = 64 << 20
+	fs.Var((*bodySize)(&c.maxBodySize), "http.max-body-size",
+		"Set the maximum request body `size` (e.g. 64Mi or 1GB). Negative or zero disables the limit.")
 	fs.StringVar(&c.basicAuth, "http.auth-basic", "",
 		"Configure basic auth credentials with format `user:pass`.")
 
@@ -113,6 +115,32 @@ func (c *ServerCommand) InitializeFlags(fs *flag.FlagSet) {
 	fs.Var(getopt.NegatedBool(&c.auth), "http.no-auth", "Disable basic auth, making the server available to all.")
 }
 
+// bodySize is a [getopt.BytesVar] which also accepts negative sizes (e.g. -1 or -64Mi), disabling the body size limit
+// like zero does.
+type bodySize getopt.BytesVar
+
+func (b *bodySize) String() string {
+	return (*getopt.BytesVar)(b).String()
+}
+
+func (b *bodySize) Set(value string) error {
+	size, negative := strings.CutPrefix(value, "-")
+
+	if err := (*getopt.BytesVar)(b).Set(size); err != nil {
+		return err
+	}
+
+	if negative {
+		*b = 0
+	}
+
+	return nil
+}
+
+func (b *bodySize) Get() any {
+	return (*getopt.BytesVar)(b).Get()
+}
+
 func (c *ServerCommand) Initialize(ctx context.Context, args []string) error {
 	if len(args) != 0 {
 		fmt.Fprintf(os.Stderr, "error: too many arguments: %v\n", args)
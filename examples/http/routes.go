@@ -40,7 +40,7 @@ func (c *ServerCommand) route(h http.HandlerFunc) http.HandlerFunc {
 		}
 
 		// configure max body size
-		if c.maxBodySize >= 0 {
+		if c.maxBodySize > 0 {
 			r.Body = http.MaxBytesReader(w, r.Body, c.maxBodySize)
 		}
 
@@ -5,27 +5,136 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
 	"regexp"
+	"runtime/debug"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/brandon1024/cmder/getopt"
 )
 
+// helpCommandName is the name of the help subcommand recognized when [WithHelpCommand] is given.
+const helpCommandName = "help"
+
 // ErrIllegalCommandConfiguration is an error returned when a [Command] provided to [Execute] is illegal.
 var ErrIllegalCommandConfiguration = errors.New("cmder: illegal command configuration")
 
+// ErrIllegalExecuteOptions is an error returned when the [ExecuteOption] options provided to [Execute] are illegal.
+var ErrIllegalExecuteOptions = errors.New("cmder: illegal execute options")
+
+// ErrUnknownCommand is an error returned when [Execute] is given an unknown subcommand similar to the name of a known
+// subcommand (see [WithSuggestions]), or when [ExecuteRequest] is given a path naming an unknown subcommand.
+var ErrUnknownCommand = errors.New("cmder: unknown command")
+
 // ErrEnvironmentBindFailure is an error returned when [Execute] failed to update a flag value from environment
 // variables (see [WithEnvironmentBinding]).
 var ErrEnvironmentBindFailure = errors.New("cmder: failed to update flag from environment variable")
 
+// ErrConfigFileBindFailure is an error returned when [Execute] failed to update flag values from a configuration file
+// (see [WithConfigFile]).
+var ErrConfigFileBindFailure = errors.New("cmder: failed to update flags from configuration file")
+
+// ErrCommandPanicked is an error returned by [Execute] when a lifecycle routine panics and panics are recovered (see
+// [WithRecover]).
+var ErrCommandPanicked = errors.New("cmder: command panicked")
+
+// CommandError is an error returned by [Execute] when a lifecycle routine of a command fails (or when usage, help or
+// version information is rendered for a command). CommandError describes the command which failed and wraps the
+// underlying error, which can be inspected with [errors.Is], [errors.As] and [errors.Unwrap]:
+//
+//	var cmdErr *cmder.CommandError
+//	if errors.As(err, &cmdErr) {
+//		fmt.Fprintf(os.Stderr, "%s: %v\n", strings.Join(cmdErr.Path, " "), cmdErr.Err)
+//	}
+type CommandError struct {
+	// The command which failed.
+	Command Command
+
+	// The sequence of command names from the root command to the command which failed (inclusive).
+	Path []string
+
+	// The underlying error.
+	Err error
+
+	// Whether usage was rendered for the command (see [ErrShowUsage]).
+	ShowUsage bool
+}
+
+// Error returns the message of the underlying error.
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// ExitError is an error which carries the exit status the application should terminate with. Lifecycle routines may
+// return an ExitError to signal a specific exit status, which callers of [Execute] can extract with [ExitCode]:
+//
+//	func (c *CheckCommand) Run(ctx context.Context, args []string) error {
+//		if !healthy {
+//			return &cmder.ExitError{Code: 3, Err: errors.New("service unhealthy")}
+//		}
+//		return nil
+//	}
+//
+// Execute never terminates the process itself.
+type ExitError struct {
+	// The exit status.
+	Code int
+
+	// The underlying error, if any.
+	Err error
+}
+
+// Error returns the message of the underlying error, or describes the exit status if there is no underlying error.
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit status for an error returned by [Execute]: 0 if err is nil, the Code of the first
+// [ExitError] in the tree of err (see [errors.As]), or 1 otherwise.
+//
+//	os.Exit(cmder.ExitCode(cmder.Execute(ctx, cmd)))
+//
+// Errors like [ErrShowUsage] are treated like any other error. To assign them an exit status, translate them into an
+// ExitError with [WithErrorMapper].
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	return 1
+}
+
 // Execute runs a [Command].
 //
 // # Execution Lifecycle
 //
 // When executing a command, Execute will call the [Runnable] Run() routine of your command. If the command also
 // implements [Initializer] or [Destroyer], the Initialize() or Destroy() routines will be invoked before
-// and after calling Run().
+// and after calling Run(). If the command implements [Precondition], Check() is invoked before Initialize().
 //
 // If the command implements [RootCommand] and a subcommand is invoked, Execute will invoke the [Initializer] and
 // [Destroyer] routines of parent and child commands:
@@ -37,21 +146,33 @@ var ErrEnvironmentBindFailure = errors.New("cmder: failed to update flag from en
 //  5. Root  [Destroyer] Destroy()
 //
 // If a command implements [RootCommand] but the first argument passed to the command doesn't match a recognized child
-// command Name(), the Run() routine will be executed.
+// command Name(), the Run() routine will be executed. To render usage instead when no arguments are given, see
+// [WithUsageOnEmptyArgs].
 //
 // # Error Handling
 //
 // Whenever a lifecycle routine (Initialize(), Run(), Destroy()) returns a non-nil error, execution is aborted
 // immediately and the error is returned at once. For example, returning an error from Run() will prevent execution of
-// Destroy() of the current command and any parents.
+// Destroy() of the current command and any parents. To run Destroy() regardless, see [WithAlwaysDestroy].
 //
-// Execute may return [ErrIllegalCommandConfiguration] if a command is misconfigured.
+// Errors returned by lifecycle routines are wrapped in a [CommandError] describing the failed command. To signal a
+// specific exit status, lifecycle routines may return an [ExitError] (see [ExitCode]).
+//
+// Execute may return [ErrIllegalCommandConfiguration] if a command is misconfigured, or [ErrIllegalExecuteOptions] if
+// the given options are invalid. To translate errors before they are returned, see [WithErrorMapper]. Panics in
+// lifecycle routines are not recovered unless configured with [WithRecover].
+//
+// To run code before or after the command tree is executed (e.g. to configure logging), see [WithPreRun] and
+// [WithPostRun]. To report slow executions, see [WithSlowCommandWarning].
 //
 // # Command Contexts
 //
 // A [context.Context] derived from ctx is passed to all lifecycle routines. The context is cancelled when Execute
 // returns. Commands should use this context to manage their resources correctly.
 //
+// The context also carries a [Store] shared by all commands, which parent commands can use to pass values to their
+// subcommands (see [StoreFrom]), and the parsed flag set of the command (see [FlagSetFromContext]).
+//
 // # Execution Options
 //
 // Execute accepts one or more [ExecuteOption] options. You can provide these options to tweak the behavior of Execute.
@@ -60,38 +181,83 @@ var ErrEnvironmentBindFailure = errors.New("cmder: failed to update flag from en
 //
 // If the command also implements [FlagInitializer], InitializeFlags() will be invoked to register additional
 // command-line flags. Each command/subcommand is given a unique [flag.FlagSet]. Help flags ('-h', '--help') are
-// configured automatically if not defined and will instruct Execute to render command usage.
+// configured automatically if not defined and will instruct Execute to render command usage. To disable help flags, see
+// [WithNoHelpFlags].
 //
 // Execute parses getopt-style (GNU/POSIX) command-line arguments with the help of package [getopt]. To use the standard
 // [flag] syntax instead, see [WithNativeFlags]. Flags and arguments cannot be interspersed by default. You can change
 // this behavior with [WithInterspersedArgs].
 //
-// To bind environment variables to flags, see [WithEnvironmentBinding].
+// To bind environment variables to flags, see [WithEnvironmentBinding]. To bind a configuration file to flags, see
+// [WithConfigFile].
 //
 // # Usage and Help Texts
 //
 // Unless explicitly overridden by the command, the '-h' flag instructs Execute to render command usage information to
 // stdout and return [ErrShowUsage]. The default usage text includes a usage synopsis, subcommands and flags. The
-// format of the usage text can be adjusted (see [WithUsageTemplate]). Returning [ErrShowUsage] from a command's
-// Initialize or Run routines will also instruct Execute to render usage.
+// format of the usage text can be adjusted (see [WithUsageTemplate]). Returning [ErrShowUsage] from any lifecycle
+// routine of a command (Check, Initialize, Run or Destroy) will also instruct Execute to render usage for that command
+// before returning the error, so commands don't need to render usage themselves.
 //
 // Likewise, the '--help' flag instructs Execute to render extended help usage information to stdout, returning
 // [ErrShowHelp]. The format may be adjusted (see [WithHelpTemplate]).
+//
+// If the command implements [VersionedCommand], the '--version' flag instructs Execute to render the command version to
+// stdout, returning [ErrShowVersion].
+//
+// When usage, help or version information is rendered for a command, the lifecycle routines of that command are not
+// run. Check() and Initialize() of its parent commands still run (e.g. for 'tool sub -h'), since parents are
+// initialized before the flags of subcommands are parsed, but no Destroy() routine is run. Callers can tell these
+// outcomes apart from a successful run with [errors.Is], for instance to exit with status 0:
+//
+//	err := cmder.Execute(ctx, cmd)
+//	if errors.Is(err, cmder.ErrShowUsage) || errors.Is(err, cmder.ErrShowHelp) {
+//		os.Exit(0)
+//	}
 func Execute(ctx context.Context, cmd Command, op ...ExecuteOption) error {
+	// prepare executor options
+	ops := newExecuteOptions(op...)
+
+	err := executeCommand(ctx, cmd, ops)
+
+	// rewrite the error (if applicable)
+	if err != nil && ops.errorMapper != nil {
+		err = ops.errorMapper(err)
+	}
+
+	return err
+}
+
+// executeCommand builds the call stack for cmd and executes it.
+func executeCommand(ctx context.Context, cmd Command, ops *ExecuteOptions) error {
 	// do some checks
 	if cmd == nil {
 		return errors.Join(ErrIllegalCommandConfiguration, errors.New("cmder: command cannot be nil"))
 	}
 
-	// prepare executor options
-	ops := &ExecuteOptions{
-		args:          os.Args[1:],
-		usageTemplate: DefaultUsageTemplate,
-		helpTemplate:  DefaultHelpTemplate,
-		outputWriter:  os.Stdout,
+	// compute arguments (if applicable)
+	if ops.argsFunc != nil {
+		args, err := ops.argsFunc()
+		if err != nil {
+			return errors.Join(ErrIllegalExecuteOptions, fmt.Errorf("cmder: failed to compute arguments: %w", err))
+		}
+
+		ops.args = args
+	}
+
+	// read additional arguments (if applicable)
+	if ops.argsReader != nil {
+		args, err := readArgs(ops.argsReader)
+		if err != nil {
+			return err
+		}
+
+		ops.args = append(slices.Clone(ops.args), args...)
 	}
-	for _, f := range op {
-		f(ops)
+
+	// read configuration file (if applicable)
+	if err := readConfigFile(ops); err != nil {
+		return err
 	}
 
 	// build a stack of command invocations
@@ -100,7 +266,53 @@ func Execute(ctx context.Context, cmd Command, op ...ExecuteOption) error {
 		return err
 	}
 
-	return execute(ctx, stack, ops)
+	return run(ctx, stack, ops)
+}
+
+// run executes the command stack, invoking the pre-run and post-run hooks (if applicable) before and after. Hooks are
+// not invoked when usage, help or version information is rendered. Slow executions are reported as configured with
+// [WithSlowCommandWarning].
+func run(ctx context.Context, stack []command, ops *ExecuteOptions) error {
+	informational := slices.ContainsFunc(stack, func(c command) bool {
+		return c.showUsage || c.showHelp || c.showVersion
+	})
+
+	// timed here rather than through the pre-run and post-run hooks: those are owned by WithPreRun and WithPostRun, and
+	// aren't given the path of the executed command
+	if ops.slowWriter != nil && !informational {
+		defer warnSlow(stack, ops, ops.clock())
+	}
+
+	// seed a store shared by all commands in the stack
+	ctx = context.WithValue(ctx, storeKey{}, &Store{})
+
+	if ops.preRun != nil && !informational {
+		if err := ops.preRun(ctx, stack[0].args); err != nil {
+			return stack[0].error(err)
+		}
+	}
+
+	err := execute(ctx, stack, ops)
+
+	if ops.postRun != nil && !informational {
+		if postErr := ops.postRun(ctx, stack[0].args); postErr != nil {
+			err = errors.Join(err, stack[0].error(postErr))
+		}
+	}
+
+	return err
+}
+
+// warnSlow writes a warning to the writer configured with [WithSlowCommandWarning] if the execution of stack, started
+// at start, took longer than the configured threshold.
+func warnSlow(stack []command, ops *ExecuteOptions, start time.Time) {
+	elapsed := ops.clock().Sub(start)
+	if elapsed <= ops.slowThreshold {
+		return
+	}
+
+	_, _ = fmt.Fprintf(ops.slowWriter, "warning: command '%s' took %s (threshold %s)\n",
+		strings.Join(stack[len(stack)-1].path, " "), elapsed, ops.slowThreshold)
 }
 
 // execute traverses the command stack recursively executing the lifecycle routines at each level.
@@ -109,49 +321,103 @@ func execute(ctx context.Context, stack []command, ops *ExecuteOptions) error {
 		return nil
 	}
 
-	// setup context
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
 	var (
 		this = stack[0]
 		err  error
 	)
 
+	// setup context, exposing the flag set of the command
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, flagSetKey{}, this.fs))
+	defer cancel()
+
 	// run init (if applicable)
-	if err := this.onInit(ctx, ops); err != nil {
-		return err
+	if err := protect(ops, func() error { return this.onInit(ctx, ops) }); err != nil {
+		return this.error(err)
 	}
 
 	// if this is a leaf, run, otherwise recurse
 	if len(stack) == 1 {
-		err = this.run(ctx, ops)
+		err = this.error(protect(ops, func() error { return this.run(ctx, ops) }))
 	} else {
 		err = execute(ctx, stack[1:], ops)
 	}
-	if err != nil {
+	if err != nil && !ops.alwaysDestroy {
 		return err
 	}
 
-	// run destroy (if applicable)
-	if err := this.onDestroy(ctx, ops); err != nil {
+	// run destroy (if applicable), preserving errors from deeper commands (see WithAlwaysDestroy)
+	destroyErr := this.error(protect(ops, func() error { return this.onDestroy(ctx, ops) }))
+	if err != nil && destroyErr != nil {
+		return errors.Join(err, destroyErr)
+	}
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return destroyErr
+}
+
+// protect invokes fn, converting a panic into an error wrapping [ErrCommandPanicked] if panics are recovered (see
+// [WithRecover]). The error describes the recovered value and includes the stack trace of the panic. If the recovered
+// value is an error, it is wrapped as well.
+func protect(ops *ExecuteOptions, fn func() error) (err error) {
+	if !ops.recoverPanics {
+		return fn()
+	}
+
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+
+		cause, ok := r.(error)
+		if !ok {
+			cause = fmt.Errorf("%v", r)
+		}
+
+		err = errors.Join(ErrCommandPanicked, fmt.Errorf("cmder: recovered from panic: %w\n\n%s", cause, debug.Stack()))
+	}()
+
+	return fn()
 }
 
 // An internal representation of a command or subcommand and it's state before execution.
 type command struct {
 	Command
 
-	fs        *flag.FlagSet
-	args      []string
-	showUsage bool
-	showHelp  bool
+	fs          *flag.FlagSet
+	path        []string
+	inherited   map[string]command
+	persistent  []string
+	builtin     []string
+	args        []string
+	showUsage   bool
+	showHelp    bool
+	showVersion bool
+}
+
+// Path returns the sequence of command names from the root command to c (inclusive), separated by spaces (e.g.
+// 'git remote add'). Usage and help templates can use this to render the full command path.
+func (c command) Path() string {
+	return strings.Join(c.path, " ")
 }
 
-// onInit calls the [Initializer] init routine if present on c.
+// error wraps a non-nil err returned by a lifecycle routine of c in a [CommandError].
+func (c command) error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &CommandError{
+		Command:   c.Command,
+		Path:      slices.Clone(c.path),
+		Err:       err,
+		ShowUsage: errors.Is(err, ErrShowUsage),
+	}
+}
+
+// onInit calls the [Precondition] check and [Initializer] init routines if present on c.
 func (c command) onInit(ctx context.Context, ops *ExecuteOptions) error {
 	var err error
 
@@ -161,8 +427,15 @@ func (c command) onInit(ctx context.Context, ops *ExecuteOptions) error {
 	if c.showHelp {
 		return errors.Join(ErrShowHelp, help(c, ops))
 	}
+	if c.showVersion {
+		return errors.Join(ErrShowVersion, version(c, ops))
+	}
+
+	if cmd, ok := c.Command.(Precondition); ok {
+		err = cmd.Check(ctx)
+	}
 
-	if cmd, ok := c.Command.(Initializer); ok {
+	if cmd, ok := c.Command.(Initializer); ok && err == nil {
 		err = cmd.Initialize(ctx, c.args)
 	}
 
@@ -184,6 +457,9 @@ func (c command) run(ctx context.Context, ops *ExecuteOptions) error {
 	if c.showHelp {
 		return errors.Join(ErrShowHelp, help(c, ops))
 	}
+	if c.showVersion {
+		return errors.Join(ErrShowVersion, version(c, ops))
+	}
 
 	err := c.Run(ctx, c.args)
 
@@ -221,70 +497,268 @@ func buildCallStack(cmd Command, ops *ExecuteOptions) ([]command, error) {
 	var stack []command
 
 	var (
-		args = ops.args
-		err  error
+		args    = ops.args
+		path    []string
+		parent  *command
+		helping bool
 	)
 
 	for cmd != nil {
-		this := command{
-			Command: cmd,
-			fs:      flag.NewFlagSet(cmd.Name(), flag.ContinueOnError),
-		}
-
-		this.fs.Usage = func() {}
+		path = append(path, cmd.Name())
 
-		if c, ok := cmd.(FlagInitializer); ok {
-			c.InitializeFlags(this.fs)
+		this, err := initCommand(cmd, parent, path, ops)
+		if err != nil {
+			return nil, err
 		}
 
-		// add help flags
-		if this.fs.Lookup("h") == nil {
-			this.fs.BoolVar(&this.showUsage, "h", false, "show command usage information")
-		}
-		if this.fs.Lookup("help") == nil {
-			this.fs.BoolVar(&this.showHelp, "help", false, "show command help information")
+		// args following the help command name are subcommand names, not flags
+		if helping {
+			this.args = args
+		} else if this.args, err = parseArgs(*this, args, ops); err != nil {
+			// prefix with command name, so users can tell which command rejected the args
+			return nil, fmt.Errorf("%s: %w", cmd.Name(), err)
 		}
 
-		// bind environment variables
-		if ops.bindEnv {
-			if err := bindEnvironmentFlags(stack, this, ops); err != nil {
-				return nil, err
-			}
-		}
+		args = this.args
 
-		this.args, err = parseArgs(this, args, ops)
+		subcommands, err := dispatchSubcommands(cmd)
 		if err != nil {
 			return nil, err
 		}
 
-		args = this.args
+		// if help command given for the root command, resolve the remaining args as a subcommand path
+		if ops.helpCommand && len(stack) == 0 && len(args) > 0 && args[0] == helpCommandName {
+			if _, ok := subcommands[helpCommandName]; !ok {
+				helping = true
+				args = args[1:]
+			}
+		}
+
+		// render usage for root commands invoked without args
+		if ops.usageOnEmpty && len(args) == 0 && len(subcommands) > 0 {
+			this.showUsage = true
+		}
 
 		if len(args) == 0 {
 			// if no subcommand name given, stop here
 			cmd = nil
-		} else if sub, ok := collectSubcommands(cmd)[args[0]]; ok {
+		} else if sub, ok := lookupSubcommand(cmd, subcommands, args[0], len(stack) == 0, ops); ok {
 			// if subcommand name given, continue
 			args = args[1:]
 			cmd = sub
 		} else {
+			// if arg given is similar to a subcommand name, suggest it
+			if ops.suggestions && !helping {
+				if suggestion := suggestSubcommand(subcommands, args[0]); suggestion != "" {
+					return nil, fmt.Errorf("%w \"%s\", did you mean \"%s\"?", ErrUnknownCommand, args[0], suggestion)
+				}
+			}
+
 			// if arg given but it's not a subcommand name, stop here
 			cmd = nil
 		}
 
-		stack = append(stack, this)
+		stack = append(stack, *this)
+		parent = this
+	}
+
+	// render usage for the command named by the help command
+	if helping {
+		stack[len(stack)-1].showUsage = true
 	}
 
 	return stack, nil
 }
 
+// initCommand builds the internal representation of cmd (see newCommand) and binds flag values from the configuration
+// file and environment variables (if applicable). The path is the sequence of command names from the root command to
+// cmd (inclusive).
+//
+// Returns an error if cmd exceeds the maximum command depth (see [WithMaxDepth]).
+func initCommand(cmd Command, parent *command, path []string, ops *ExecuteOptions) (*command, error) {
+	if ops.maxDepth > 0 && len(path) > ops.maxDepth {
+		return nil, errors.Join(ErrIllegalCommandConfiguration,
+			fmt.Errorf("cmder: command '%s' exceeds maximum command depth of %d", strings.Join(path, " "),
+				ops.maxDepth))
+	}
+
+	this, err := newCommand(cmd, parent, path, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	// bind configuration file
+	if err := bindConfigFlags(*this, ops); err != nil {
+		return nil, err
+	}
+
+	rearmFlags(*this)
+
+	// bind environment variables
+	if ops.bindEnv {
+		if err := bindEnvironmentFlags(*this, ops); err != nil {
+			return nil, err
+		}
+	}
+
+	rearmFlags(*this)
+
+	return this, nil
+}
+
+// lookupSubcommand returns the command that name dispatches to from cmd, given the subcommands of cmd (see
+// dispatchSubcommands). In addition, the root command dispatches to positional dispatch targets (see
+// [WithPositionalDispatch]) and the completion and version commands (if enabled).
+func lookupSubcommand(cmd Command, subcommands map[string]Command, name string, root bool,
+	ops *ExecuteOptions) (Command, bool) {
+	if sub, ok := subcommands[name]; ok {
+		return sub, true
+	}
+
+	if !root {
+		return nil, false
+	}
+
+	if sub, ok := ops.dispatch[name]; ok {
+		return sub, true
+	}
+	if name == completionCommandName && ops.completion {
+		return newCompletionCommand(cmd, ops), true
+	}
+	if name == versionCommandName && ops.versionCommand {
+		return newVersionCommand(cmd, ops.version, ops.versionBuild, ops.outputWriter), true
+	}
+
+	return nil, false
+}
+
+// newCommand builds the internal representation of cmd, initializing its flags. The path is the sequence of command
+// names from the root command to cmd (inclusive). Persistent flags of parent (and its ancestors) are registered
+// alongside the flags of cmd, if parent is non-nil. Help flags are registered unless disabled in ops.
+//
+// Returns an error if a persistent flag collides with another flag of cmd.
+func newCommand(cmd Command, parent *command, path []string, ops *ExecuteOptions) (*command, error) {
+	this := &command{
+		Command: cmd,
+		fs:      flag.NewFlagSet(cmd.Name(), ops.flagErrorHandling),
+		path:    slices.Clone(path),
+	}
+
+	this.fs.Usage = func() {}
+
+	if c, ok := cmd.(FlagInitializer); ok {
+		c.InitializeFlags(this.fs)
+	}
+
+	// add persistent flags
+	if err := this.registerPersistentFlags(parent); err != nil {
+		return nil, err
+	}
+
+	// add help flags
+	if this.fs.Lookup("h") == nil && !ops.noHelpFlags {
+		this.fs.BoolVar(&this.showUsage, "h", false, "show command usage information")
+		this.builtin = append(this.builtin, "h")
+	}
+	if this.fs.Lookup("help") == nil && !ops.noHelpFlags {
+		this.fs.BoolVar(&this.showHelp, "help", false, "show command help information")
+		this.builtin = append(this.builtin, "help")
+	}
+
+	// add version flag
+	if _, ok := cmd.(VersionedCommand); ok && this.fs.Lookup("version") == nil {
+		this.fs.BoolVar(&this.showVersion, "version", false, "show command version information")
+		this.builtin = append(this.builtin, "version")
+	}
+
+	return this, nil
+}
+
+// registerPersistentFlags registers the persistent flags of c (see [PersistentFlagInitializer]) and the persistent
+// flags inherited from parent and its ancestors (if parent is non-nil) with the flagset of c. Inherited flags share the
+// [flag.Value] of the flag registered by the command declaring them.
+//
+// Returns an error if a persistent flag collides with another flag of c.
+func (c *command) registerPersistentFlags(parent *command) error {
+	c.inherited = map[string]command{}
+
+	if parent != nil {
+		maps.Copy(c.inherited, parent.inherited)
+
+		for _, name := range parent.persistent {
+			c.inherited[name] = *parent
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.inherited)) {
+		owner := c.inherited[name]
+
+		if err := c.addPersistentFlag(owner.fs.Lookup(name), owner); err != nil {
+			return err
+		}
+	}
+
+	p, ok := c.Command.(PersistentFlagInitializer)
+	if !ok {
+		return nil
+	}
+
+	fs := flag.NewFlagSet(c.fs.Name(), flag.ContinueOnError)
+	p.InitializePersistentFlags(fs)
+
+	var err error
+	fs.VisitAll(func(flg *flag.Flag) {
+		if err == nil {
+			err = c.addPersistentFlag(flg, *c)
+		}
+		if err == nil {
+			c.persistent = append(c.persistent, flg.Name)
+		}
+	})
+
+	return err
+}
+
+// addPersistentFlag registers the persistent flag flg declared by command owner with the flagset of c, returning an
+// error if c already has a flag by that name.
+func (c *command) addPersistentFlag(flg *flag.Flag, owner command) error {
+	if c.fs.Lookup(flg.Name) != nil {
+		return errors.Join(ErrIllegalCommandConfiguration,
+			fmt.Errorf("cmder: persistent flag '%s' of command '%s' collides with flag of command '%s'", flg.Name,
+				owner.Path(), c.Path()))
+	}
+
+	c.fs.Var(flg.Value, flg.Name, flg.Usage)
+	c.fs.Lookup(flg.Name).DefValue = flg.DefValue
+
+	return nil
+}
+
 // parseArgs processes args for the given command, returning the unparsed (remaining) arguments.
 func parseArgs(cmd command, args []string, ops *ExecuteOptions) ([]string, error) {
-	var fp flagParser = &getopt.PosixFlagSet{
+	// parse errors are returned here, and the error handling policy of the flagset (see WithFlagErrorHandling) is
+	// applied by flagError
+	if ops.flagErrorHandling != flag.ContinueOnError {
+		cmd.fs.Init(cmd.fs.Name(), flag.ContinueOnError)
+		defer cmd.fs.Init(cmd.fs.Name(), ops.flagErrorHandling)
+
+		// the standard library renders usage after reporting parse errors
+		cmd.fs.Usage = func() {
+			_ = usage(cmd, ops)
+		}
+	}
+
+	// requirements of flags (see getopt.MarkRequired) are checked once all args are parsed, since interspersed args are
+	// parsed in batches
+	posix := &getopt.PosixFlagSet{
 		FlagSet:        cmd.fs,
 		RelaxedParsing: ops.relaxedFlags,
+		DeferRequired:  true,
 		Usage:          func() {},
 	}
 
+	var fp flagParser = posix
+
 	if ops.nativeFlags {
 		fp = cmd.fs
 	}
@@ -297,50 +771,148 @@ func parseArgs(cmd command, args []string, ops *ExecuteOptions) ([]string, error
 
 	var processed []string
 
-	for len(args) > 0 {
+	for {
 		if err := fp.Parse(args); err != nil {
-			return nil, err
+			return nil, flagError(cmd, err, ops)
 		}
 
 		args = fp.Args()
 
-		if !interspersed {
-			return args, nil
+		if !interspersed || len(args) == 0 {
+			break
 		}
 
-		if len(args) > 0 {
-			processed = append(processed, args[0])
-			args = args[1:]
+		processed, args = append(processed, args[0]), args[1:]
+	}
+
+	if !ops.nativeFlags {
+		if err := posix.CheckRequired(); err != nil {
+			return nil, flagError(cmd, err, ops)
 		}
 	}
 
+	if !interspersed {
+		return args, nil
+	}
+
 	return processed, nil
 }
 
-// bindEnvironmentFlags sets flag values from matching environment variables.
-func bindEnvironmentFlags(stack []command, cmd command, ops *ExecuteOptions) error {
-	var components []string
+// flagError applies the error handling policy of ops (see [WithFlagErrorHandling]) to err, an error returned when
+// parsing the flags of cmd. Like the standard library, the error and the usage of cmd are reported before exiting or
+// panicking. Returns err if parse errors are returned to the caller.
+func flagError(cmd command, err error, ops *ExecuteOptions) error {
+	if ops.flagErrorHandling == flag.ContinueOnError {
+		return err
+	}
 
-	for _, c := range stack {
-		components = append(components, c.Name())
+	// the standard library reports the error and renders usage itself
+	if !ops.nativeFlags {
+		if !errors.Is(err, flag.ErrHelp) {
+			_, _ = fmt.Fprintln(cmd.fs.Output(), err)
+		}
+
+		_ = usage(cmd, ops)
+	}
+
+	if ops.flagErrorHandling == flag.PanicOnError {
+		panic(err)
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+
+	os.Exit(2)
+	return nil
+}
+
+// readArgs reads whitespace-separated arguments from r.
+func readArgs(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("cmder: failed to read arguments: %w", err)
+	}
+
+	args, err := getopt.SplitArgs(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("cmder: failed to read arguments: %w", err)
+	}
+
+	return args, nil
+}
+
+// readConfigFile reads the configuration file given by [WithConfigFile] (if applicable) into ops.
+func readConfigFile(ops *ExecuteOptions) error {
+	if ops.configLoader == nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(ops.configPath)
+	if errors.Is(err, fs.ErrNotExist) && ops.configOptional {
+		return nil
+	}
+	if err != nil {
+		return errors.Join(ErrConfigFileBindFailure, fmt.Errorf("cmder: failed to read configuration file: %w", err))
+	}
+
+	// never nil, so that empty files are still given to the loader
+	ops.configData = append([]byte{}, data...)
+
+	return nil
+}
+
+// bindConfigFlags sets flag values from the configuration file read by readConfigFile (if applicable). The loader is
+// given a flag set sharing the flag values of cmd, so that flags updated by the loader are not marked as set.
+func bindConfigFlags(cmd command, ops *ExecuteOptions) error {
+	if ops.configLoader == nil || ops.configData == nil {
+		return nil
+	}
+
+	flags := flag.NewFlagSet(cmd.fs.Name(), flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	// inherited flags are bound by the command declaring them
+	cmd.fs.VisitAll(func(flg *flag.Flag) {
+		if _, ok := cmd.inherited[flg.Name]; !ok {
+			flags.Var(flg.Value, flg.Name, flg.Usage)
+		}
+	})
+
+	if err := ops.configLoader(ops.configData, flags); err != nil {
+		return errors.Join(
+			ErrConfigFileBindFailure,
+			fmt.Errorf("cmder: failed to load configuration file %s for command '%s': %w", ops.configPath,
+				cmd.Path(), err),
+		)
 	}
 
-	components = append(components, cmd.Name())
+	return nil
+}
 
+// bindEnvironmentFlags sets flag values from matching environment variables.
+func bindEnvironmentFlags(cmd command, ops *ExecuteOptions) error {
+	// inherited flags are bound by the command declaring them
 	var flags []*flag.Flag
 	cmd.fs.VisitAll(func(f *flag.Flag) {
-		flags = append(flags, f)
+		if _, ok := cmd.inherited[f.Name]; !ok {
+			flags = append(flags, f)
+		}
 	})
 
 	for _, flag := range flags {
-		variable := ops.bindEnvPrefix + formatEnvvar(append(components, flag.Name))
+		variable := envVariable(cmd, flag.Name, ops)
 
 		if value, ok := os.LookupEnv(variable); ok {
+			if isBoolFlag(flag.Value) {
+				value = envBool(value)
+			}
+
 			if err := flag.Value.Set(value); err != nil {
 				return errors.Join(
 					ErrEnvironmentBindFailure,
-					fmt.Errorf("cmder: failed to set flag %s from variable %s", flag.Name, variable),
-					err,
+					fmt.Errorf("cmder: invalid value %q for environment variable %s bound to flag %s: %w", value,
+						variable, flagDisplayName(flag.Name, ops), err),
 				)
 			}
 		}
@@ -349,6 +921,70 @@ func bindEnvironmentFlags(stack []command, cmd command, ops *ExecuteOptions) err
 	return nil
 }
 
+// rearmFlags rearms flags declared by cmd which replace their values when first set (see [getopt.ResetStringsVar]),
+// so that values given by the next source (e.g. the command line after environment variables) replace the values
+// bound from the previous one.
+func rearmFlags(cmd command) {
+	cmd.fs.VisitAll(func(flg *flag.Flag) {
+		if _, ok := cmd.inherited[flg.Name]; ok {
+			return
+		}
+
+		for v := flg.Value; v != nil; {
+			if r, ok := v.(interface{ Rearm() }); ok {
+				r.Rearm()
+				return
+			}
+
+			w, ok := v.(interface{ Unwrap() flag.Value })
+			if !ok {
+				return
+			}
+
+			v = w.Unwrap()
+		}
+	})
+}
+
+// flagDisplayName returns the flag name as given at the command line (e.g. '-v' or '--verbose').
+func flagDisplayName(name string, ops *ExecuteOptions) string {
+	if len(name) == 1 || ops.nativeFlags {
+		return "-" + name
+	}
+
+	return "--" + name
+}
+
+// envBool maps common boolean words found in environment variables ('yes', 'no', 'on', 'off', 'y', 'n') to values
+// parseable by [strconv.ParseBool]. Other values are returned as-is.
+func envBool(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "y", "on":
+		return "true"
+	case "no", "n", "off":
+		return "false"
+	default:
+		return value
+	}
+}
+
+// envVariable returns the name of the environment variable bound to the flag with the given name.
+func envVariable(cmd command, name string, ops *ExecuteOptions) string {
+	path := append(slices.Clone(cmd.path), name)
+	prefix := ops.bindEnvPrefix
+
+	// commands may bind their flags in their own namespace
+	if c, ok := cmd.Command.(EnvPrefixCommand); ok {
+		path, prefix = []string{name}, c.EnvPrefix()
+	}
+
+	if ops.bindEnvNameFunc != nil {
+		return prefix + ops.bindEnvNameFunc(path)
+	}
+
+	return prefix + formatEnvvar(path)
+}
+
 // formatEnvvar generates an environment variable name which maps to the given flag path.
 func formatEnvvar(flagPath []string) string {
 	reg := regexp.MustCompile("[^a-zA-Z0-9]+")
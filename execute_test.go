@@ -1,9 +1,19 @@
 package cmder
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -188,5 +198,1486 @@ func TestExecute(t *testing.T) {
 			err := Execute(t.Context(), cmd, WithArgs([]string{"-h"}))
 			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
 		})
+
+		t.Run("should signal help was shown without running command", func(t *testing.T) {
+			var ran bool
+
+			cmd := &BaseCommand{
+				CommandName: "help-cmd",
+				RunFunc: func(ctx context.Context, args []string) error {
+					ran = true
+					return nil
+				},
+			}
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"--help"}), WithOutputWriter(io.Discard))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowHelp))
+			tutil.Assert(t, tutil.Eq(false, ran))
+
+			err = Execute(t.Context(), cmd, WithArgs([]string{}))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq(true, ran))
+		})
+
+		t.Run("should only skip lifecycle routines of command rendering usage", func(t *testing.T) {
+			var calls []string
+
+			record := func(name string) func(context.Context, []string) error {
+				return func(context.Context, []string) error {
+					calls = append(calls, name)
+					return nil
+				}
+			}
+
+			cmd := Tree(New("root").Init(record("root.init")).Destroy(record("root.destroy")).Sub(
+				New("sub").Init(record("sub.init")).Run(record("sub.run")).Destroy(record("sub.destroy")),
+			))
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"sub", "-h"}), WithOutputWriter(io.Discard))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Match([]string{"root.init"}, calls))
+		})
+	})
+
+	t.Run("version flag", func(t *testing.T) {
+		cmd := &versionedCommand{
+			BaseCommand: BaseCommand{
+				CommandName: "tool",
+				Children: []Command{
+					&versionedCommand{
+						BaseCommand: BaseCommand{CommandName: "plugin"},
+						version:     "plugin v0.3.1",
+					},
+					&BaseCommand{CommandName: "other"},
+				},
+			},
+			version: "tool v1.2.0",
+		}
+
+		t.Run("should render version of root command", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"--version"}), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowVersion))
+			tutil.Assert(t, tutil.Eq("tool v1.2.0\n", buf.String()))
+		})
+
+		t.Run("should render version of subcommand", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"plugin", "--version"}), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowVersion))
+			tutil.Assert(t, tutil.Eq("plugin v0.3.1\n", buf.String()))
+		})
+
+		t.Run("should not register version flag for unversioned commands", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"other", "--version"}), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.Eq(false, err == nil))
+			tutil.Assert(t, tutil.Eq(false, errors.Is(err, ErrShowVersion)))
+		})
+	})
+
+	t.Run("usage on empty args", func(t *testing.T) {
+		var ran []string
+
+		cmd := &BaseCommand{
+			CommandName: "root",
+			RunFunc: func(ctx context.Context, args []string) error {
+				ran = append(ran, "root")
+				return nil
+			},
+			Children: []Command{
+				&BaseCommand{
+					CommandName: "child",
+					RunFunc: func(ctx context.Context, args []string) error {
+						ran = append(ran, "child")
+						return nil
+					},
+				},
+			},
+		}
+
+		t.Run("should render usage if root command invoked without args", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			ran = nil
+
+			err := Execute(t.Context(), cmd, WithArgs(nil), WithUsageOnEmptyArgs(), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(0, len(ran)))
+			tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(buf.String(), "Usage:")))
+		})
+
+		t.Run("should run subcommand normally", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			ran = nil
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"child"}), WithUsageOnEmptyArgs(), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"child"}, ran))
+			tutil.Assert(t, tutil.Eq(0, buf.Len()))
+		})
+
+		t.Run("should run root command without option", func(t *testing.T) {
+			ran = nil
+
+			err := Execute(t.Context(), cmd, WithArgs(nil))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"root"}, ran))
+		})
+	})
+
+	t.Run("positional dispatch", func(t *testing.T) {
+		var ran []string
+
+		record := func(name string) func(context.Context, []string) error {
+			return func(ctx context.Context, args []string) error {
+				ran = append(append(ran, name), args...)
+				return nil
+			}
+		}
+
+		root := &BaseCommand{CommandName: "tool", RunFunc: record("tool")}
+
+		dispatch := map[string]Command{
+			"start": &BaseCommand{CommandName: "start", RunFunc: record("start")},
+			"stop":  &BaseCommand{CommandName: "stop", RunFunc: record("stop")},
+		}
+
+		t.Run("should dispatch on first positional argument", func(t *testing.T) {
+			ran = nil
+
+			err := Execute(t.Context(), root, WithArgs([]string{"stop", "now"}), WithPositionalDispatch(dispatch))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"stop", "now"}, ran))
+		})
+
+		t.Run("should fall back to root command", func(t *testing.T) {
+			ran = nil
+
+			err := Execute(t.Context(), root, WithArgs([]string{"restart", "now"}), WithPositionalDispatch(dispatch))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"tool", "restart", "now"}, ran))
+		})
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		cmd := Tree(New("a").Sub(New("b").Sub(New("c"))))
+
+		t.Run("should return error if command stack exceeds max depth", func(t *testing.T) {
+			err := Execute(t.Context(), cmd, WithArgs([]string{"b", "c"}), WithMaxDepth(2))
+			tutil.Assert(t, tutil.IsErr(err, ErrIllegalCommandConfiguration))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(err.Error(), "'a b c' exceeds maximum command depth of 2")))
+		})
+
+		t.Run("should execute command within max depth", func(t *testing.T) {
+			err := Execute(t.Context(), cmd, WithArgs([]string{"b", "c"}), WithMaxDepth(3))
+			tutil.Assert(t, tutil.NilErr(err))
+		})
+
+		t.Run("should guard against cyclic subcommands", func(t *testing.T) {
+			cyclic := &BaseCommand{CommandName: "loop"}
+			cyclic.Children = []Command{cyclic}
+
+			args := slices.Repeat([]string{"loop"}, 100)
+
+			err := Execute(t.Context(), cyclic, WithArgs(args), WithMaxDepth(10))
+			tutil.Assert(t, tutil.IsErr(err, ErrIllegalCommandConfiguration))
+		})
+	})
+
+	t.Run("environment binding", func(t *testing.T) {
+		var feature bool
+
+		cmd := func() Command {
+			return Tree(New("tool").Flags(func(fs *flag.FlagSet) {
+				fs.BoolVar(&feature, "feature", false, "enable feature")
+			}))
+		}
+
+		for value, expected := range map[string]bool{"yes": true, "no": false, "ON": true, "off": false, "1": true} {
+			t.Run("should bind bool flag from "+value, func(t *testing.T) {
+				t.Setenv("TOOL_FEATURE", value)
+				feature = !expected
+
+				err := Execute(t.Context(), cmd(), WithArgs([]string{}), WithEnvironmentBinding())
+				tutil.Assert(t, tutil.NilErr(err))
+				tutil.Assert(t, tutil.Eq(expected, feature))
+			})
+		}
+
+		t.Run("should bind variables with custom prefix", func(t *testing.T) {
+			t.Setenv("MYAPP_TOOL_FEATURE", "true")
+			feature = false
+
+			err := Execute(t.Context(), cmd(), WithArgs([]string{}), WithEnvironmentBinding(EnvPrefix("MYAPP_")))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq(true, feature))
+		})
+
+		t.Run("should bind variables with custom names", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			t.Setenv("MYAPP.tool.feature", "true")
+			feature = false
+
+			err := Execute(t.Context(), cmd(), WithArgs([]string{}), WithEnvironmentBinding(EnvPrefix("MYAPP."),
+				EnvNameFunc(func(path []string) string {
+					return strings.Join(path, ".")
+				})))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq(true, feature))
+
+			err = Execute(t.Context(), cmd(), WithArgs([]string{"-h"}), WithEnvironmentBinding(EnvPrefix("MYAPP."),
+				EnvNameFunc(func(path []string) string {
+					return strings.Join(path, ".")
+				})), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), "enable feature (env MYAPP.tool.feature)")))
+		})
+
+		t.Run("should bind variables with command prefix", func(t *testing.T) {
+			var (
+				buf           bytes.Buffer
+				bucket, token string
+			)
+
+			t.Setenv("STORAGE_BUCKET", "backups")
+			t.Setenv("TOOL_TOKEN", "secret")
+			t.Setenv("TOOL_STORAGE_BUCKET", "ignored")
+
+			cmd := Tree(New("tool").Flags(func(fs *flag.FlagSet) {
+				fs.StringVar(&token, "token", "", "api token")
+			}))
+			cmd.Children = append(cmd.Children, &envPrefixCommand{
+				BaseCommand: *Tree(New("storage").Flags(func(fs *flag.FlagSet) {
+					fs.StringVar(&bucket, "bucket", "", "bucket name")
+				}).Run(func(ctx context.Context, args []string) error {
+					return nil
+				})),
+				prefix: "STORAGE_",
+			})
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"storage"}), WithEnvironmentBinding())
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq("backups", bucket))
+			tutil.Assert(t, tutil.Eq("secret", token))
+
+			err = Execute(t.Context(), cmd, WithArgs([]string{"storage", "-h"}), WithEnvironmentBinding(),
+				WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), "bucket name (env STORAGE_BUCKET)")))
+		})
+
+		t.Run("should replace values of reset flags bound to variables", func(t *testing.T) {
+			var tags []string
+
+			t.Setenv("TOOL_TAG", "env1,env2")
+
+			cmd := Tree(New("tool").Flags(func(fs *flag.FlagSet) {
+				tags = []string{"default"}
+				fs.Var(getopt.ResetStrings(&tags), "tag", "image `tag`")
+			}))
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{}), WithEnvironmentBinding())
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"env1", "env2"}, tags))
+
+			err = Execute(t.Context(), cmd, WithArgs([]string{"--tag", "a", "--tag", "b"}), WithEnvironmentBinding())
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"a", "b"}, tags))
+		})
+
+		t.Run("should return error for malformed bool", func(t *testing.T) {
+			t.Setenv("TOOL_FEATURE", "maybe")
+
+			err := Execute(t.Context(), cmd(), WithArgs([]string{}), WithEnvironmentBinding())
+			tutil.Assert(t, tutil.IsErr(err, ErrEnvironmentBindFailure))
+		})
+
+		t.Run("should return error for malformed value before running", func(t *testing.T) {
+			var (
+				count int
+				ran   bool
+			)
+
+			t.Setenv("TOOL_COUNT", "banana")
+
+			cmd := Tree(New("tool").Flags(func(fs *flag.FlagSet) {
+				fs.IntVar(&count, "count", 0, "count")
+			}).Run(func(ctx context.Context, args []string) error {
+				ran = true
+				return nil
+			}))
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{}), WithEnvironmentBinding())
+			tutil.Assert(t, tutil.IsErr(err, ErrEnvironmentBindFailure))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(err.Error(),
+				`cmder: invalid value "banana" for environment variable TOOL_COUNT bound to flag --count: `)))
+			tutil.Assert(t, tutil.Eq(false, ran))
+		})
+	})
+
+	t.Run("config file", func(t *testing.T) {
+		var (
+			format, output string
+			count          int
+		)
+
+		cmd := func() Command {
+			return Tree(New("tool").Flags(func(fs *flag.FlagSet) {
+				fs.StringVar(&format, "format", "", "format")
+				fs.StringVar(&output, "output", "", "output")
+				fs.IntVar(&count, "count", 0, "count")
+			}).Sub(New("sub").Run(func(ctx context.Context, args []string) error {
+				return nil
+			})).Run(func(ctx context.Context, args []string) error {
+				return nil
+			}))
+		}
+
+		loader := func(data []byte, fs *flag.FlagSet) error {
+			for line := range strings.Lines(string(data)) {
+				name, value, _ := strings.Cut(strings.TrimSpace(line), "=")
+				if fs.Lookup(name) == nil {
+					continue
+				}
+
+				if err := fs.Set(name, value); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		write := func(t *testing.T, contents string) string {
+			path := filepath.Join(t.TempDir(), "tool.conf")
+			tutil.Assert(t, tutil.NilErr(os.WriteFile(path, []byte(contents), 0o600)))
+			return path
+		}
+
+		t.Run("should bind flags with lowest precedence", func(t *testing.T) {
+			format, output, count = "", "", 0
+			t.Setenv("TOOL_OUTPUT", "env.txt")
+
+			path := write(t, "format=json\noutput=conf.txt\ncount=1\n")
+
+			err := Execute(t.Context(), cmd(), WithArgs([]string{"--count", "3"}), WithEnvironmentBinding(),
+				WithConfigFile(path, loader))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq("json", format))
+			tutil.Assert(t, tutil.Eq("env.txt", output))
+			tutil.Assert(t, tutil.Eq(3, count))
+		})
+
+		t.Run("should not mark flags as set", func(t *testing.T) {
+			var (
+				flags   *flag.FlagSet
+				visited []string
+			)
+
+			path := write(t, "format=json\n")
+
+			cmd := Tree(New("tool").Flags(func(fs *flag.FlagSet) {
+				flags = fs
+				fs.StringVar(&format, "format", "", "format")
+				fs.IntVar(&count, "count", 0, "count")
+			}).Run(func(ctx context.Context, args []string) error {
+				return nil
+			}))
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"--count", "3"}), WithConfigFile(path, loader))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq("json", format))
+
+			flags.Visit(func(f *flag.Flag) {
+				visited = append(visited, f.Name)
+			})
+			tutil.Assert(t, tutil.Match([]string{"count"}, visited))
+		})
+
+		t.Run("should return error for missing file", func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "missing.conf")
+
+			err := Execute(t.Context(), cmd(), WithArgs([]string{}), WithConfigFile(path, loader))
+			tutil.Assert(t, tutil.IsErr(err, ErrConfigFileBindFailure))
+			tutil.Assert(t, tutil.IsErr(err, fs.ErrNotExist))
+		})
+
+		t.Run("should ignore missing file if configured", func(t *testing.T) {
+			format = ""
+			path := filepath.Join(t.TempDir(), "missing.conf")
+
+			err := Execute(t.Context(), cmd(), WithArgs([]string{"--format", "yaml"}),
+				WithConfigFile(path, loader, IgnoreMissingConfigFile()))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq("yaml", format))
+		})
+
+		t.Run("should return loader errors", func(t *testing.T) {
+			path := write(t, "count=banana\n")
+
+			err := Execute(t.Context(), cmd(), WithArgs([]string{"sub"}), WithConfigFile(path, loader))
+			tutil.Assert(t, tutil.IsErr(err, ErrConfigFileBindFailure))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(err.Error(), "for command 'tool'")))
+		})
+	})
+
+	t.Run("subcommand aliases", func(t *testing.T) {
+		var ran []string
+
+		run := func(name string) func(context.Context, []string) error {
+			return func(ctx context.Context, args []string) error {
+				ran = append(ran, name)
+				return nil
+			}
+		}
+
+		tree := func() Command {
+			return Tree(
+				New("tool").Sub(
+					New("generate").Aliases("gen", "g").Run(run("generate")),
+					New("get").Run(run("get")),
+				),
+			)
+		}
+
+		t.Run("should dispatch subcommand by alias", func(t *testing.T) {
+			ran = nil
+
+			for _, name := range []string{"generate", "gen", "g"} {
+				err := Execute(t.Context(), tree(), WithArgs([]string{name}))
+				tutil.Assert(t, tutil.NilErr(err))
+			}
+
+			tutil.Assert(t, tutil.Match([]string{"generate", "generate", "generate"}, ran))
+		})
+
+		t.Run("should not list aliases in usage", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), tree(), WithArgs([]string{"-h"}), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(false, strings.Contains(buf.String(), "gen ")))
+		})
+
+		t.Run("should return error if alias collides with sibling command", func(t *testing.T) {
+			cmd := Tree(
+				New("tool").Sub(
+					New("generate").Aliases("get").Run(run("generate")),
+					New("get").Run(run("get")),
+				),
+			)
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"get"}))
+			tutil.Assert(t, tutil.IsErr(err, ErrIllegalCommandConfiguration))
+			tutil.Assert(t, tutil.Eq(true,
+				strings.Contains(err.Error(), "alias 'get' of command 'generate' collides with command 'get'")))
+		})
+	})
+
+	t.Run("suggestions", func(t *testing.T) {
+		var args []string
+
+		tree := func() Command {
+			return Tree(
+				New("tool").Run(func(ctx context.Context, a []string) error {
+					args = a
+					return nil
+				}).Sub(
+					New("server"),
+					New("status"),
+					New("debug").Hidden(),
+				),
+			)
+		}
+
+		t.Run("should suggest similar subcommand", func(t *testing.T) {
+			err := Execute(t.Context(), tree(), WithArgs([]string{"serv"}), WithSuggestions())
+			tutil.Assert(t, tutil.IsErr(err, ErrUnknownCommand))
+			tutil.Assert(t, tutil.Eq(`cmder: unknown command "serv", did you mean "server"?`, err.Error()))
+		})
+
+		t.Run("should pass dissimilar args to parent command", func(t *testing.T) {
+			err := Execute(t.Context(), tree(), WithArgs([]string{"file.txt"}), WithSuggestions())
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"file.txt"}, args))
+		})
+
+		t.Run("should not suggest hidden subcommands", func(t *testing.T) {
+			err := Execute(t.Context(), tree(), WithArgs([]string{"debgu"}), WithSuggestions())
+			tutil.Assert(t, tutil.NilErr(err))
+		})
+
+		t.Run("should not suggest unless enabled", func(t *testing.T) {
+			err := Execute(t.Context(), tree(), WithArgs([]string{"serv"}))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"serv"}, args))
+		})
+	})
+
+	t.Run("no help flags", func(t *testing.T) {
+		t.Run("should reject undefined help flags", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			for _, arg := range []string{"-h", "--help"} {
+				err := Execute(t.Context(), Tree(New("tool")), WithArgs([]string{arg}), WithNoHelpFlags(),
+					WithOutputWriter(&buf))
+				tutil.Assert(t, tutil.IsErr(err, flag.ErrHelp))
+				tutil.Assert(t, tutil.Eq(false, errors.Is(err, ErrShowUsage) || errors.Is(err, ErrShowHelp)))
+			}
+
+			tutil.Assert(t, tutil.Eq("", buf.String()))
+		})
+
+		t.Run("should let command handle help flags", func(t *testing.T) {
+			var (
+				buf  bytes.Buffer
+				help bool
+			)
+
+			cmd := Tree(New("tool").Flags(func(fs *flag.FlagSet) {
+				fs.BoolVar(&help, "help", false, "custom help")
+			}).Run(func(ctx context.Context, args []string) error {
+				return nil
+			}))
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"--help"}), WithNoHelpFlags(), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq(true, help))
+			tutil.Assert(t, tutil.Eq("", buf.String()))
+		})
+	})
+
+	t.Run("help command", func(t *testing.T) {
+		var ran bool
+
+		run := func(ctx context.Context, args []string) error {
+			ran = true
+			return nil
+		}
+
+		tree := func() Command {
+			return Tree(
+				New("root").Run(run).Sub(
+					New("remote").ShortHelp("manage remotes").Run(run).Sub(
+						New("add").Usage("root remote add <name> <url>").Run(run),
+					),
+				),
+			)
+		}
+
+		t.Run("should render usage for nested subcommand", func(t *testing.T) {
+			var buf bytes.Buffer
+			ran = false
+
+			err := Execute(t.Context(), tree(), WithArgs([]string{"help", "remote", "add"}), WithHelpCommand(),
+				WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(false, ran))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), "root remote add <name> <url>")))
+		})
+
+		t.Run("should render usage for root command", func(t *testing.T) {
+			var buf bytes.Buffer
+			ran = false
+
+			err := Execute(t.Context(), tree(), WithArgs([]string{"help"}), WithHelpCommand(), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(false, ran))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), "manage remotes")))
+		})
+
+		t.Run("should not treat subcommand args as flags", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), tree(), WithArgs([]string{"help", "remote", "--unknown"}), WithHelpCommand(),
+				WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		})
+
+		t.Run("should prefer help subcommand of root command", func(t *testing.T) {
+			ran = false
+
+			err := Execute(t.Context(), Tree(New("root").Sub(New("help").Run(run))), WithArgs([]string{"help"}),
+				WithHelpCommand())
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq(true, ran))
+		})
+
+		t.Run("should run command with help arg unless enabled", func(t *testing.T) {
+			ran = false
+
+			err := Execute(t.Context(), tree(), WithArgs([]string{"help", "remote"}))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq(true, ran))
+		})
+	})
+
+	t.Run("pre-run and post-run hooks", func(t *testing.T) {
+		var lifecycle []string
+
+		record := func(s string) func(context.Context, []string) error {
+			return func(ctx context.Context, args []string) error {
+				lifecycle = append(lifecycle, s)
+				return nil
+			}
+		}
+
+		hook := func(s string, err error) func(context.Context, []string) error {
+			return func(ctx context.Context, args []string) error {
+				lifecycle = append(lifecycle, fmt.Sprintf("%s %v", s, args))
+				return err
+			}
+		}
+
+		tree := func(run func(context.Context, []string) error) Command {
+			return Tree(
+				New("root").Flags(func(fs *flag.FlagSet) {
+					fs.Bool("v", false, "verbose")
+				}).Init(record("root-init")).Destroy(record("root-destroy")).Sub(
+					New("child").Init(record("child-init")).Run(run).Destroy(record("child-destroy")),
+				),
+			)
+		}
+
+		t.Run("should invoke hooks around command tree", func(t *testing.T) {
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(record("child-run")), WithArgs([]string{"-v", "child", "arg"}),
+				WithPreRun(hook("pre-run", nil)), WithPostRun(hook("post-run", nil)))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{
+				"pre-run [child arg]", "root-init", "child-init", "child-run", "child-destroy", "root-destroy",
+				"post-run [child arg]",
+			}, lifecycle))
+		})
+
+		t.Run("should abort execution if pre-run fails", func(t *testing.T) {
+			errAuth := errors.New("unauthorized")
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(record("child-run")), WithArgs([]string{"child"}),
+				WithPreRun(hook("pre-run", errAuth)), WithPostRun(hook("post-run", nil)))
+			tutil.Assert(t, tutil.IsErr(err, errAuth))
+			tutil.Assert(t, tutil.Match([]string{"pre-run [child]"}, lifecycle))
+		})
+
+		t.Run("should invoke post-run if command fails", func(t *testing.T) {
+			errFailed, errFlush := errors.New("failed"), errors.New("flush failed")
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(hook("child-run", errFailed)), WithArgs([]string{"child"}),
+				WithPostRun(hook("post-run", errFlush)))
+			tutil.Assert(t, tutil.IsErr(err, errFailed))
+			tutil.Assert(t, tutil.IsErr(err, errFlush))
+			tutil.Assert(t, tutil.Match([]string{"root-init", "child-init", "child-run []", "post-run [child]"},
+				lifecycle))
+		})
+
+		t.Run("should not invoke hooks when rendering usage", func(t *testing.T) {
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(record("child-run")), WithArgs([]string{"child", "-h"}),
+				WithPreRun(hook("pre-run", nil)), WithPostRun(hook("post-run", nil)), WithOutputWriter(io.Discard))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Match([]string{"root-init"}, lifecycle))
+		})
+	})
+
+	t.Run("slow command warning", func(t *testing.T) {
+		var now time.Time
+
+		// advance the clock instead of sleeping
+		clock := func(ops *ExecuteOptions) {
+			ops.clock = func() time.Time {
+				return now
+			}
+		}
+
+		tree := func(d time.Duration) Command {
+			return Tree(
+				New("root").Sub(
+					New("child").Run(func(ctx context.Context, args []string) error {
+						now = now.Add(d)
+						return nil
+					}),
+				),
+			)
+		}
+
+		t.Run("should warn if execution exceeds threshold", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), tree(3*time.Second), WithArgs([]string{"child"}),
+				WithSlowCommandWarning(time.Second, &buf), clock)
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq("warning: command 'root child' took 3s (threshold 1s)\n", buf.String()))
+		})
+
+		t.Run("should not warn if execution within threshold", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), tree(time.Second), WithArgs([]string{"child"}),
+				WithSlowCommandWarning(time.Second, &buf), clock)
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq("", buf.String()))
+		})
+
+		t.Run("should not warn when rendering usage", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), tree(3*time.Second), WithArgs([]string{"child", "-h"}),
+				WithSlowCommandWarning(0, &buf), WithOutputWriter(io.Discard), clock)
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq("", buf.String()))
+		})
+	})
+
+	t.Run("exit codes", func(t *testing.T) {
+		tree := func(err error) Command {
+			return Tree(
+				New("root").Sub(
+					New("child").Run(func(ctx context.Context, args []string) error {
+						return err
+					}),
+				),
+			)
+		}
+
+		t.Run("should return zero for nil error", func(t *testing.T) {
+			err := Execute(t.Context(), tree(nil), WithArgs([]string{"child"}))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq(0, ExitCode(err)))
+		})
+
+		t.Run("should return one for generic errors", func(t *testing.T) {
+			err := Execute(t.Context(), tree(errors.New("failed")), WithArgs([]string{"child"}))
+			tutil.Assert(t, tutil.Eq(1, ExitCode(err)))
+		})
+
+		t.Run("should return code of exit error", func(t *testing.T) {
+			errUnhealthy := errors.New("unhealthy")
+
+			err := Execute(t.Context(), tree(&ExitError{Code: 3, Err: errUnhealthy}), WithArgs([]string{"child"}))
+			tutil.Assert(t, tutil.IsErr(err, errUnhealthy))
+			tutil.Assert(t, tutil.Eq("unhealthy", err.Error()))
+			tutil.Assert(t, tutil.Eq(3, ExitCode(err)))
+		})
+
+		t.Run("should return code of exit error from error mapper", func(t *testing.T) {
+			err := Execute(t.Context(), tree(nil), WithArgs([]string{"child", "-h"}), WithOutputWriter(io.Discard),
+				WithErrorMapper(func(err error) error {
+					if errors.Is(err, ErrShowUsage) {
+						return &ExitError{Code: 2, Err: err}
+					}
+					return err
+				}))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(2, ExitCode(err)))
+		})
+
+		t.Run("should describe exit error without underlying error", func(t *testing.T) {
+			tutil.Assert(t, tutil.Eq("exit status 4", (&ExitError{Code: 4}).Error()))
+		})
 	})
+
+	t.Run("always destroy", func(t *testing.T) {
+		var lifecycle []string
+
+		hook := func(s string, err error) func(context.Context, []string) error {
+			return func(ctx context.Context, args []string) error {
+				lifecycle = append(lifecycle, s)
+				return err
+			}
+		}
+
+		errFailed, errClose := errors.New("failed"), errors.New("close failed")
+
+		tree := func(run, destroy error) Command {
+			return Tree(
+				New("root").Init(hook("root-init", nil)).Destroy(hook("root-destroy", nil)).Sub(
+					New("child").Init(hook("child-init", nil)).Run(hook("child-run", run)).
+						Destroy(hook("child-destroy", destroy)),
+				),
+			)
+		}
+
+		t.Run("should not invoke destroy after run fails by default", func(t *testing.T) {
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(errFailed, nil), WithArgs([]string{"child"}))
+			tutil.Assert(t, tutil.IsErr(err, errFailed))
+			tutil.Assert(t, tutil.Match([]string{"root-init", "child-init", "child-run"}, lifecycle))
+		})
+
+		t.Run("should invoke destroy after run fails", func(t *testing.T) {
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(errFailed, nil), WithArgs([]string{"child"}), WithAlwaysDestroy())
+			tutil.Assert(t, tutil.IsErr(err, errFailed))
+			tutil.Assert(t, tutil.Match([]string{
+				"root-init", "child-init", "child-run", "child-destroy", "root-destroy",
+			}, lifecycle))
+
+			var cmdErr *CommandError
+			tutil.Assert(t, tutil.Eq(true, errors.As(err, &cmdErr)))
+			tutil.Assert(t, tutil.Match([]string{"root", "child"}, cmdErr.Path))
+		})
+
+		t.Run("should join destroy errors with original error", func(t *testing.T) {
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(errFailed, errClose), WithArgs([]string{"child"}), WithAlwaysDestroy())
+			tutil.Assert(t, tutil.IsErr(err, errFailed))
+			tutil.Assert(t, tutil.IsErr(err, errClose))
+			tutil.Assert(t, tutil.Match([]string{
+				"root-init", "child-init", "child-run", "child-destroy", "root-destroy",
+			}, lifecycle))
+		})
+
+		t.Run("should return destroy error if run succeeds", func(t *testing.T) {
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(nil, errClose), WithArgs([]string{"child"}), WithAlwaysDestroy())
+			tutil.Assert(t, tutil.IsErr(err, errClose))
+			tutil.Assert(t, tutil.Match([]string{
+				"root-init", "child-init", "child-run", "child-destroy", "root-destroy",
+			}, lifecycle))
+		})
+	})
+
+	t.Run("recover", func(t *testing.T) {
+		var destroyed bool
+
+		tree := func(value any) Command {
+			return Tree(
+				New("root").Destroy(func(ctx context.Context, args []string) error {
+					destroyed = true
+					return nil
+				}).Sub(
+					New("child").Run(func(ctx context.Context, args []string) error {
+						panic(value)
+					}),
+				),
+			)
+		}
+
+		t.Run("should convert panic into error", func(t *testing.T) {
+			destroyed = false
+
+			err := Execute(t.Context(), tree("boom"), WithArgs([]string{"child"}), WithRecover())
+			tutil.Assert(t, tutil.IsErr(err, ErrCommandPanicked))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(err.Error(), "recovered from panic: boom")))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(err.Error(), "runtime/debug.Stack")))
+			tutil.Assert(t, tutil.Eq(false, destroyed))
+
+			var cmdErr *CommandError
+			tutil.Assert(t, tutil.Eq(true, errors.As(err, &cmdErr)))
+			tutil.Assert(t, tutil.Match([]string{"root", "child"}, cmdErr.Path))
+		})
+
+		t.Run("should wrap recovered errors", func(t *testing.T) {
+			errBoom := errors.New("boom")
+
+			err := Execute(t.Context(), tree(errBoom), WithArgs([]string{"child"}), WithRecover())
+			tutil.Assert(t, tutil.IsErr(err, ErrCommandPanicked))
+			tutil.Assert(t, tutil.IsErr(err, errBoom))
+		})
+
+		t.Run("should invoke destroy after panic if configured", func(t *testing.T) {
+			destroyed = false
+
+			err := Execute(t.Context(), tree("boom"), WithArgs([]string{"child"}), WithRecover(), WithAlwaysDestroy())
+			tutil.Assert(t, tutil.IsErr(err, ErrCommandPanicked))
+			tutil.Assert(t, tutil.Eq(true, destroyed))
+		})
+
+		t.Run("should not recover by default", func(t *testing.T) {
+			defer func() {
+				tutil.Assert(t, tutil.Eq[any]("boom", recover()))
+			}()
+
+			_ = Execute(t.Context(), tree("boom"), WithArgs([]string{"child"}))
+			t.Fatalf("no panic")
+		})
+	})
+
+	t.Run("flag set context", func(t *testing.T) {
+		var parent, child *flag.FlagSet
+
+		cmd := Tree(
+			New("root").Flags(func(fs *flag.FlagSet) {
+				fs.Bool("v", false, "verbose")
+			}).Init(func(ctx context.Context, args []string) error {
+				parent = FlagSetFromContext(ctx)
+				return nil
+			}).Sub(
+				New("child").Flags(func(fs *flag.FlagSet) {
+					fs.Int("limit", 10, "limit results")
+				}).Run(func(ctx context.Context, args []string) error {
+					child = FlagSetFromContext(ctx)
+					return nil
+				}),
+			),
+		)
+
+		t.Run("should expose flag set of each command", func(t *testing.T) {
+			err := Execute(t.Context(), cmd, WithArgs([]string{"-v", "child", "--limit", "5"}))
+			tutil.Assert(t, tutil.NilErr(err))
+
+			tutil.Assert(t, tutil.Eq(true, parent != nil && child != nil))
+			tutil.Assert(t, tutil.Eq(true, parent.Lookup("limit") == nil))
+			tutil.Assert(t, tutil.Eq("true", parent.Lookup("v").Value.String()))
+			tutil.Assert(t, tutil.Eq("5", child.Lookup("limit").Value.String()))
+
+			fs := &getopt.PosixFlagSet{FlagSet: child}
+			tutil.Assert(t, tutil.Eq(true, fs.Changed("limit")))
+		})
+
+		t.Run("should return nil without flag set", func(t *testing.T) {
+			tutil.Assert(t, tutil.Eq(true, FlagSetFromContext(t.Context()) == nil))
+		})
+	})
+
+	t.Run("persistent flags", func(t *testing.T) {
+		var (
+			verbose bool
+			limit   int
+		)
+
+		tree := func() Command {
+			return Tree(
+				New("tool").PersistentFlags(func(fs *flag.FlagSet) {
+					fs.BoolVar(&verbose, "verbose", false, "verbose output")
+					fs.BoolVar(&verbose, "v", false, "verbose output")
+				}).Sub(
+					New("remote").Sub(
+						New("list").Flags(func(fs *flag.FlagSet) {
+							fs.IntVar(&limit, "limit", 10, "limit results")
+						}).Run(func(ctx context.Context, args []string) error {
+							return nil
+						}),
+					),
+				),
+			)
+		}
+
+		t.Run("should parse persistent flags at any level", func(t *testing.T) {
+			for _, args := range [][]string{
+				{"--verbose", "remote", "list"},
+				{"remote", "-v", "list"},
+				{"remote", "list", "--limit", "5", "--verbose"},
+			} {
+				verbose = false
+
+				err := Execute(t.Context(), tree(), WithArgs(args))
+				tutil.Assert(t, tutil.NilErr(err))
+				tutil.Assert(t, tutil.Eq(true, verbose))
+			}
+		})
+
+		t.Run("should render persistent flags as global flags of subcommands", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), tree(), WithArgs([]string{"remote", "list", "-h"}), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(true, strings.HasSuffix(buf.String(),
+				"\nGlobal Flags:\n  -v, --verbose\n      verbose output\n")))
+		})
+
+		t.Run("should render persistent flags as flags of declaring command", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), tree(), WithArgs([]string{"-h"}), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), "Flags:\n")))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), "  -v, --verbose\n")))
+			tutil.Assert(t, tutil.Eq(false, strings.Contains(buf.String(), "Global Flags:")))
+		})
+
+		t.Run("should bind persistent flags to environment of declaring command", func(t *testing.T) {
+			t.Setenv("TOOL_VERBOSE", "true")
+			t.Setenv("TOOL_REMOTE_LIST_VERBOSE", "false")
+			verbose = false
+
+			err := Execute(t.Context(), tree(), WithArgs([]string{"remote", "list"}), WithEnvironmentBinding())
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq(true, verbose))
+		})
+
+		t.Run("should return error if persistent flag collides with flag of subcommand", func(t *testing.T) {
+			cmd := Tree(
+				New("tool").PersistentFlags(func(fs *flag.FlagSet) {
+					fs.Bool("verbose", false, "verbose output")
+				}).Sub(
+					New("list").Flags(func(fs *flag.FlagSet) {
+						fs.Int("verbose", 0, "verbosity level")
+					}),
+				),
+			)
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"list"}))
+			tutil.Assert(t, tutil.IsErr(err, ErrIllegalCommandConfiguration))
+			tutil.Assert(t, tutil.Eq(true, strings.Contains(err.Error(),
+				"persistent flag 'verbose' of command 'tool' collides with flag of command 'tool list'")))
+		})
+	})
+
+	t.Run("precondition", func(t *testing.T) {
+		var lifecycle []string
+
+		record := func(s string) func(context.Context, []string) error {
+			return func(ctx context.Context, args []string) error {
+				lifecycle = append(lifecycle, s)
+				return nil
+			}
+		}
+
+		errOffline := errors.New("offline")
+
+		tree := func(check func(context.Context) error) Command {
+			return Tree(
+				New("root").Init(record("root-init")).Destroy(record("root-destroy")).Sub(
+					New("child").Check(check).Init(record("child-init")).Run(record("child-run")),
+				),
+			)
+		}
+
+		t.Run("should abort before initialize if precondition fails", func(t *testing.T) {
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(func(ctx context.Context) error {
+				lifecycle = append(lifecycle, "child-check")
+				return errOffline
+			}), WithArgs([]string{"child"}))
+			tutil.Assert(t, tutil.IsErr(err, errOffline))
+			tutil.Assert(t, tutil.Match([]string{"root-init", "child-check"}, lifecycle))
+		})
+
+		t.Run("should run command if precondition is met", func(t *testing.T) {
+			lifecycle = nil
+
+			err := Execute(t.Context(), tree(func(ctx context.Context) error {
+				lifecycle = append(lifecycle, "child-check")
+				return nil
+			}), WithArgs([]string{"child"}))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"root-init", "child-check", "child-init", "child-run", "root-destroy"},
+				lifecycle))
+		})
+	})
+
+	t.Run("args reader", func(t *testing.T) {
+		var (
+			output string
+			args   []string
+		)
+
+		cmd := &BaseCommand{
+			CommandName: "reader",
+			InitFlagsFunc: func(fs *flag.FlagSet) {
+				fs.StringVar(&output, "output", "-", "output file")
+			},
+			RunFunc: func(ctx context.Context, a []string) error {
+				args = a
+				return nil
+			},
+		}
+
+		t.Run("should append arguments read from reader", func(t *testing.T) {
+			r := strings.NewReader("--output 'my file.txt'\n\"second arg\"\nthird\n")
+
+			err := Execute(t.Context(), cmd, WithArgs([]string{"first"}), WithArgsReader(r), WithInterspersedArgs())
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq("my file.txt", output))
+			tutil.Assert(t, tutil.Match([]string{"first", "second arg", "third"}, args))
+		})
+
+		t.Run("should return error for malformed arguments", func(t *testing.T) {
+			err := Execute(t.Context(), cmd, WithArgs(nil), WithArgsReader(strings.NewReader(`"unterminated`)))
+			tutil.Assert(t, tutil.Eq(true, err != nil))
+		})
+	})
+
+	t.Run("args func", func(t *testing.T) {
+		var args []string
+
+		cmd := &BaseCommand{
+			CommandName: "lazy",
+			RunFunc: func(ctx context.Context, a []string) error {
+				args = a
+				return nil
+			},
+		}
+
+		t.Run("should execute with computed arguments", func(t *testing.T) {
+			err := Execute(t.Context(), cmd, WithArgsFunc(func() ([]string, error) {
+				return []string{"from", "func"}, nil
+			}))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"from", "func"}, args))
+		})
+
+		t.Run("should return error if arguments cannot be computed", func(t *testing.T) {
+			errConfig := errors.New("missing config")
+			args = nil
+
+			err := Execute(t.Context(), cmd, WithArgsFunc(func() ([]string, error) {
+				return nil, errConfig
+			}))
+			tutil.Assert(t, tutil.IsErr(err, ErrIllegalExecuteOptions))
+			tutil.Assert(t, tutil.IsErr(err, errConfig))
+			tutil.Assert(t, tutil.Eq(0, len(args)))
+		})
+
+		t.Run("should prefer last of WithArgs and WithArgsFunc", func(t *testing.T) {
+			err := Execute(t.Context(), cmd, WithArgsFunc(func() ([]string, error) {
+				return []string{"from", "func"}, nil
+			}), WithArgs([]string{"fixed"}))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Match([]string{"fixed"}, args))
+		})
+	})
+
+	t.Run("show usage from lifecycle routines", func(t *testing.T) {
+		showUsage := func(context.Context, []string) error {
+			return ErrShowUsage
+		}
+
+		builders := map[string]func(*CommandBuilder) *CommandBuilder{
+			"check": func(b *CommandBuilder) *CommandBuilder {
+				return b.Check(func(context.Context) error { return ErrShowUsage })
+			},
+			"init":    func(b *CommandBuilder) *CommandBuilder { return b.Init(showUsage) },
+			"run":     func(b *CommandBuilder) *CommandBuilder { return b.Run(showUsage) },
+			"destroy": func(b *CommandBuilder) *CommandBuilder { return b.Destroy(showUsage) },
+		}
+
+		for name, build := range builders {
+			t.Run("should render usage of leaf command if returned from "+name, func(t *testing.T) {
+				var buf bytes.Buffer
+
+				cmd := Tree(New("root").Sub(build(New("leaf").Usage("root leaf <arg>"))))
+
+				err := Execute(t.Context(), cmd, WithArgs([]string{"leaf"}), WithOutputWriter(&buf))
+				tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+				tutil.Assert(t, tutil.Eq(1, strings.Count(buf.String(), "Usage:\n  root leaf <arg>\n")))
+			})
+		}
+	})
+
+	t.Run("flag error handling", func(t *testing.T) {
+		var (
+			handling flag.ErrorHandling
+			errBuf   bytes.Buffer
+		)
+
+		cmd := func() Command {
+			errBuf.Reset()
+
+			return Tree(New("tool").Flags(func(fs *flag.FlagSet) {
+				handling = fs.ErrorHandling()
+				fs.SetOutput(&errBuf)
+			}))
+		}
+
+		t.Run("should continue on error by default", func(t *testing.T) {
+			err := Execute(t.Context(), cmd(), WithArgs([]string{"--foo"}))
+			tutil.Assert(t, tutil.Eq("tool: flag '--foo' does not exist", err.Error()))
+			tutil.Assert(t, tutil.Eq(flag.ContinueOnError, handling))
+		})
+
+		for _, native := range []bool{false, true} {
+			t.Run(fmt.Sprintf("should panic on error if configured (native=%v)", native), func(t *testing.T) {
+				var buf bytes.Buffer
+
+				ops := []ExecuteOption{WithArgs([]string{"--foo"}), WithFlagErrorHandling(flag.PanicOnError),
+					WithOutputWriter(&buf)}
+				if native {
+					ops = append(ops, WithNativeFlags())
+				}
+
+				defer func() {
+					r := recover()
+					tutil.Assert(t, tutil.Eq(true, r != nil))
+					tutil.Assert(t, tutil.Eq(flag.PanicOnError, handling))
+					tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), "Usage:\n  tool [flags]\n")))
+					tutil.Assert(t, tutil.Eq(1, strings.Count(buf.String(), "Usage:")))
+					tutil.Assert(t, tutil.Eq(true, strings.Contains(errBuf.String(), "-foo")))
+				}()
+
+				_ = Execute(t.Context(), cmd(), ops...)
+				t.Fatalf("expected panic")
+			})
+		}
+
+		t.Run("should render usage for help flags if panicking on error", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), cmd(), WithArgs([]string{"-h"}), WithFlagErrorHandling(flag.PanicOnError),
+				WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+			tutil.Assert(t, tutil.Eq(1, strings.Count(buf.String(), "Usage:")))
+		})
+	})
+
+	t.Run("parse errors", func(t *testing.T) {
+		errInvalid := errors.New("invalid")
+
+		tree := Tree(
+			New("tool").Flags(func(fs *flag.FlagSet) {
+				fs.Bool("verbose", false, "verbose output")
+			}).Sub(
+				New("server").Flags(func(fs *flag.FlagSet) {
+					fs.Func("port", "listen port", func(string) error {
+						return errInvalid
+					})
+				}),
+			),
+		)
+
+		t.Run("should prefix error with name of subcommand", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithArgs([]string{"--verbose", "server", "--foo"}))
+			tutil.Assert(t, tutil.Eq("server: flag '--foo' does not exist", err.Error()))
+		})
+
+		t.Run("should prefix error with name of root command", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithArgs([]string{"--foo", "server"}))
+			tutil.Assert(t, tutil.Eq("tool: flag '--foo' does not exist", err.Error()))
+		})
+
+		t.Run("should wrap underlying error", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithArgs([]string{"server", "--port=80"}))
+			tutil.Assert(t, tutil.IsErr(err, errInvalid))
+			tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(err.Error(), "server: ")))
+		})
+	})
+
+	t.Run("required flags", func(t *testing.T) {
+		tree := Tree(
+			New("tool").Sub(
+				New("deploy").Flags(func(fs *flag.FlagSet) {
+					fs.String("env", "", "target `environment`")
+					getopt.Alias(fs, "env", "e")
+					getopt.MarkRequired(fs, "env")
+					fs.Bool("canary", false, "deploy a canary release")
+					fs.Int("weight", 0, "canary traffic `percentage`")
+					getopt.MarkRequiredIf(fs, "weight", "canary")
+					fs.Var(getopt.Strings(new([]string)), "tag", "image `tag`")
+					getopt.MarkMaxOccurrences(fs, "tag", 2)
+				}),
+			),
+		)
+
+		t.Run("should return error if required flag not set", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithArgs([]string{"deploy"}))
+			tutil.Assert(t, tutil.Eq("deploy: missing required flag '--env'", err.Error()))
+		})
+
+		t.Run("should run command if required flag set through alias", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithArgs([]string{"deploy", "-e", "prod"}))
+			tutil.Assert(t, tutil.NilErr(err))
+		})
+
+		t.Run("should return error if conditionally required flag not set", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithArgs([]string{"deploy", "-e", "prod", "--canary"}))
+			tutil.Assert(t, tutil.Eq("deploy: missing flag '--weight', required when flag '--canary' is set", err.Error()))
+		})
+
+		t.Run("should check required flags once all interspersed args are parsed", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithInterspersedArgs(), WithArgs([]string{"deploy", "app", "--env", "prod"}))
+			tutil.Assert(t, tutil.NilErr(err))
+		})
+
+		t.Run("should count occurrences across interspersed args", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithInterspersedArgs(),
+				WithArgs([]string{"deploy", "-e", "prod", "--tag", "a", "app", "--tag", "b", "--tag", "c"}))
+			tutil.Assert(t, tutil.Eq("deploy: flag '--tag' given 3 times, must be given at most 2 times", err.Error()))
+		})
+	})
+
+	t.Run("command error", func(t *testing.T) {
+		errFailed := errors.New("failed")
+
+		tree := Tree(
+			New("root").Sub(
+				New("remote").Sub(
+					New("add").Run(func(ctx context.Context, args []string) error {
+						return errFailed
+					}),
+				),
+			),
+		)
+
+		t.Run("should describe failing subcommand", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithArgs([]string{"remote", "add"}))
+
+			var cmdErr *CommandError
+			tutil.Assert(t, tutil.Eq(true, errors.As(err, &cmdErr)))
+			tutil.Assert(t, tutil.Eq("add", cmdErr.Command.Name()))
+			tutil.Assert(t, tutil.Match([]string{"root", "remote", "add"}, cmdErr.Path))
+			tutil.Assert(t, tutil.Eq(errFailed, cmdErr.Err))
+			tutil.Assert(t, tutil.Eq(false, cmdErr.ShowUsage))
+			tutil.Assert(t, tutil.Eq(errFailed, errors.Unwrap(err)))
+			tutil.Assert(t, tutil.Eq("failed", err.Error()))
+		})
+
+		t.Run("should report rendered usage", func(t *testing.T) {
+			err := Execute(t.Context(), tree, WithArgs([]string{"remote", "-h"}), WithOutputWriter(io.Discard))
+
+			var cmdErr *CommandError
+			tutil.Assert(t, tutil.Eq(true, errors.As(err, &cmdErr)))
+			tutil.Assert(t, tutil.Match([]string{"root", "remote"}, cmdErr.Path))
+			tutil.Assert(t, tutil.Eq(true, cmdErr.ShowUsage))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		})
+	})
+
+	t.Run("error mapper", func(t *testing.T) {
+		var (
+			errInternal = errors.New("internal: connection refused")
+			errFriendly = errors.New("server unavailable")
+			errOther    = errors.New("other")
+			calls       int
+		)
+
+		mapper := func(err error) error {
+			calls++
+
+			if errors.Is(err, errInternal) {
+				return errFriendly
+			}
+
+			return err
+		}
+
+		cmd := func(err error) Command {
+			return Tree(New("mapped").Run(func(ctx context.Context, args []string) error {
+				return err
+			}))
+		}
+
+		t.Run("should rewrite matching error", func(t *testing.T) {
+			err := Execute(t.Context(), cmd(errInternal), WithArgs(nil), WithErrorMapper(mapper))
+			tutil.Assert(t, tutil.IsErr(err, errFriendly))
+			tutil.Assert(t, tutil.Eq(false, errors.Is(err, errInternal)))
+		})
+
+		t.Run("should pass through other errors unchanged", func(t *testing.T) {
+			err := Execute(t.Context(), cmd(errOther), WithArgs(nil), WithErrorMapper(mapper))
+			tutil.Assert(t, tutil.IsErr(err, errOther))
+			tutil.Assert(t, tutil.Eq("other", err.Error()))
+		})
+
+		t.Run("should not invoke mapper for nil errors", func(t *testing.T) {
+			calls = 0
+
+			err := Execute(t.Context(), cmd(nil), WithArgs(nil), WithErrorMapper(mapper))
+			tutil.Assert(t, tutil.NilErr(err))
+			tutil.Assert(t, tutil.Eq(0, calls))
+		})
+
+		t.Run("should rewrite configuration errors", func(t *testing.T) {
+			err := Execute(t.Context(), nil, WithErrorMapper(func(err error) error {
+				return fmt.Errorf("wrapped: %w", err)
+			}))
+			tutil.Assert(t, tutil.IsErr(err, ErrIllegalCommandConfiguration))
+			tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(err.Error(), "wrapped: ")))
+		})
+	})
+
+	t.Run("output", func(t *testing.T) {
+		t.Run("should capture output written concurrently", func(t *testing.T) {
+			var buf tutil.Buffer
+
+			cmd := &BaseCommand{
+				CommandName: "concurrent",
+				CommandDocumentation: CommandDocumentation{
+					Usage: "concurrent",
+				},
+				RunFunc: func(ctx context.Context, args []string) error {
+					var wg sync.WaitGroup
+
+					for i := range 16 {
+						wg.Add(1)
+
+						go func() {
+							defer wg.Done()
+							fmt.Fprintf(&buf, "worker %02d\n", i)
+						}()
+					}
+
+					wg.Wait()
+
+					return ErrShowUsage
+				},
+			}
+
+			err := Execute(t.Context(), cmd, WithArgs(nil), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+
+			for i := range 16 {
+				tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), fmt.Sprintf("worker %02d\n", i))))
+			}
+
+			tutil.Assert(t, tutil.Eq(1, strings.Count(buf.String(), "Usage:")))
+		})
+
+		t.Run("should isolate output of concurrent executions", func(t *testing.T) {
+			var (
+				wg   sync.WaitGroup
+				bufs [8]bytes.Buffer
+				errs [8]error
+			)
+
+			for i := range bufs {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					cmd := Tree(New(fmt.Sprintf("cmd-%d", i)))
+					errs[i] = Execute(t.Context(), cmd, WithArgs([]string{"-h"}), WithOutputWriter(&bufs[i]))
+				}()
+			}
+
+			wg.Wait()
+
+			for i := range bufs {
+				tutil.Assert(t, tutil.IsErr(errs[i], ErrShowUsage))
+				tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(bufs[i].String(), fmt.Sprintf("Usage:\n  cmd-%d ", i))))
+				tutil.Assert(t, tutil.Eq(1, strings.Count(bufs[i].String(), "Usage:")))
+			}
+		})
+	})
+}
+
+// versionedCommand is a [Command] implementing [VersionedCommand].
+type versionedCommand struct {
+	BaseCommand
+
+	version string
+}
+
+// Version returns the command version.
+func (c versionedCommand) Version() string {
+	return c.version
+}
+
+// envPrefixCommand is a [Command] implementing [EnvPrefixCommand], which binds its flags to environment variables
+// with its own prefix.
+type envPrefixCommand struct {
+	BaseCommand
+
+	prefix string
+}
+
+// EnvPrefix returns the environment variable prefix of the command.
+func (c *envPrefixCommand) EnvPrefix() string {
+	return c.prefix
 }
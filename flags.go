@@ -1,6 +1,7 @@
 package cmder
 
 import (
+	"context"
 	"flag"
 )
 
@@ -15,6 +16,42 @@ type FlagInitializer interface {
 	InitializeFlags(*flag.FlagSet)
 }
 
+// PersistentFlagInitializer is an interface implemented by a [Command] that need to register flags shared with all of
+// its subcommands, such as a global '--verbose' or '--config' flag.
+//
+// InitializePersistentFlags will be invoked during [Execute], after InitializeFlags() (see [FlagInitializer]). The
+// flags registered with the given [flag.FlagSet] are registered with the flagset of the command and of each of its
+// subcommands (recursively), sharing the same [flag.Value]. Persistent flags can therefore be given before or after
+// subcommand names:
+//
+//	tool --verbose remote add origin
+//	tool remote add --verbose origin
+//
+// Persistent flags are listed separately from the flags of subcommands in usage text (see [DefaultUsageTemplate]).
+// [Execute] returns [ErrIllegalCommandConfiguration] if a persistent flag has the same name as a flag of the command or
+// one of its subcommands.
+type PersistentFlagInitializer interface {
+	InitializePersistentFlags(*flag.FlagSet)
+}
+
+// flagSetKey is the context key of the [flag.FlagSet] of the command being executed.
+type flagSetKey struct{}
+
+// FlagSetFromContext returns the parsed [flag.FlagSet] of the command whose lifecycle routine was given ctx, or nil if
+// ctx doesn't carry a flag set (e.g. contexts not derived from one given by [Execute]). This allows commands to inspect
+// their flags without retaining the flag set given to InitializeFlags(), for instance to check which flags were set:
+//
+//	func (c *ListCommand) Run(ctx context.Context, args []string) error {
+//		fs := &getopt.PosixFlagSet{FlagSet: cmder.FlagSetFromContext(ctx)}
+//		if fs.Changed("limit") {
+//			// ...
+//		}
+//	}
+func FlagSetFromContext(ctx context.Context) *flag.FlagSet {
+	fs, _ := ctx.Value(flagSetKey{}).(*flag.FlagSet)
+	return fs
+}
+
 // flagParser is an interface implemented by types that parse args.
 type flagParser interface {
 	Parse([]string) error
@@ -3,17 +3,46 @@ package getopt
 import (
 	"flag"
 	"fmt"
+
+	"github.com/brandon1024/cmder/internal/levenshtein"
 )
 
 // Alias is a simple utility for registering flag aliases. A new flag is registered in fs with name alias and the
 // [flag.Value] of a flag named name.
 //
-// If flag name doesn't exist in fs, panic.
+// If flag name doesn't exist in fs, or a flag named alias already exists in fs, panic. The panic message suggests
+// similarly named flags to help spot typos.
 func Alias(fs *flag.FlagSet, name, alias string) {
 	flg := fs.Lookup(name)
 	if flg == nil {
-		panic(fmt.Sprintf("getopt: cannot register alias '%s': target '%s' does not exist in flag set", alias, name))
+		msg := fmt.Sprintf("getopt: cannot register alias '%s': target '%s' does not exist in flag set", alias, name)
+		if suggestion := suggestFlag(fs, name); suggestion != "" {
+			msg += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+		}
+
+		panic(msg)
+	}
+
+	if fs.Lookup(alias) != nil {
+		panic(fmt.Sprintf("getopt: cannot register alias '%s': flag '%s' already exists in flag set", alias, alias))
 	}
 
 	fs.Var(flg.Value, alias, flg.Usage)
 }
+
+// suggestFlag returns the name of the flag in fs most similar to name, or an empty string if no flag is similar
+// enough.
+func suggestFlag(fs *flag.FlagSet, name string) string {
+	var (
+		suggestion string
+		best       = max(2, len(name)/3) + 1
+	)
+
+	fs.VisitAll(func(flg *flag.Flag) {
+		if d := levenshtein.Distance(name, flg.Name); d < best {
+			suggestion, best = flg.Name, d
+		}
+	})
+
+	return suggestion
+}
@@ -6,17 +6,42 @@ import (
 )
 
 func TestAlias(t *testing.T) {
+	expectPanic := func(t *testing.T, expected string) {
+		r := recover()
+		if r == nil {
+			t.Fatalf("no panic")
+		}
+		if r != expected {
+			t.Fatalf("unexpected panic: %v", r)
+		}
+	}
+
 	t.Run("should panic if target flag does not exist", func(t *testing.T) {
-		defer func() {
-			if r := recover(); r == nil {
-				t.Fatalf("no panic")
-			}
-		}()
+		defer expectPanic(t, "getopt: cannot register alias 'q': target 'non-existent' does not exist in flag set")
 
 		fs := flag.NewFlagSet("test", flag.ContinueOnError)
 		Alias(fs, "non-existent", "q")
 	})
 
+	t.Run("should suggest similar flag if target flag does not exist", func(t *testing.T) {
+		defer expectPanic(t, "getopt: cannot register alias 'a': target 'http.bind-addr' does not exist in flag set "+
+			"(did you mean 'http.bind-address'?)")
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("http.bind-address", ":8080", "bind address")
+		fs.String("http.read-timeout", "5s", "read timeout")
+		Alias(fs, "http.bind-addr", "a")
+	})
+
+	t.Run("should panic if alias already exists", func(t *testing.T) {
+		defer expectPanic(t, "getopt: cannot register alias 'q': flag 'q' already exists in flag set")
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Bool("quiet", false, "silence the cat")
+		fs.Bool("q", false, "query")
+		Alias(fs, "quiet", "q")
+	})
+
 	t.Run("should register alias successfully", func(t *testing.T) {
 		var quiet bool
 		fs := flag.NewFlagSet("test", flag.ContinueOnError)
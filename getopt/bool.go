@@ -43,6 +43,63 @@ func (b *NegatedBoolVar) Get() any {
 	return !bool(*b)
 }
 
+// NegatableBoolVar is a boolean [flag.Value] which may be negated at the command line by prefixing the (long) flag
+// name with 'no-'. NegatableBoolVar also implements [flag.Getter].
+//
+//	--gpg-sign      // true
+//	--no-gpg-sign   // false
+//
+// The negated form does not accept a value ('--no-gpg-sign=true' is rejected) and is only recognized for long flags.
+// To register a negatable flag, see [PosixFlagSet.BoolVarN].
+type NegatableBoolVar bool
+
+// NegatableBool builds a [NegatableBoolVar] backed by b.
+func NegatableBool(b *bool) *NegatableBoolVar {
+	return (*NegatableBoolVar)(b)
+}
+
+// BoolVarN defines a [NegatableBoolVar] flag with the specified name, default value and usage string. The argument p
+// points to a bool variable in which to store the value of the flag. In addition to '--name', the flag is recognized
+// as '--no-name' which sets the flag to false.
+func (f *PosixFlagSet) BoolVarN(p *bool, name string, value bool, usage string) {
+	*p = value
+	f.Var(NegatableBool(p), name, usage)
+}
+
+// String returns the string representation of the boolean value.
+func (b *NegatableBoolVar) String() string {
+	if b == nil {
+		return strconv.FormatBool(false)
+	}
+
+	return strconv.FormatBool(bool(*b))
+}
+
+// Set updates the value of the flag. The given value must be a string parseable by [strconv.ParseBool].
+func (b *NegatableBoolVar) Set(s string) error {
+	val, err := strconv.ParseBool(s)
+	if err == nil {
+		*b = NegatableBoolVar(val)
+	}
+
+	return err
+}
+
+// IsBoolFlag marks the flag is not accepting args.
+func (b *NegatableBoolVar) IsBoolFlag() bool {
+	return true
+}
+
+// IsNegatable marks the flag as accepting a negated '--no-' form.
+func (b *NegatableBoolVar) IsNegatable() bool {
+	return true
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a bool.
+func (b *NegatableBoolVar) Get() any {
+	return bool(*b)
+}
+
 // boolFlag is a [flag.Value] that also implements a method IsBoolFlag, used to determine if the flag accepts an
 // argument or not.
 type boolFlag interface {
@@ -52,6 +109,19 @@ type boolFlag interface {
 
 // isBoolFlag checks if the given flag has a [flag.Value] which is a boolean flag.
 func isBoolFlag(flg *flag.Flag) bool {
-	bf, ok := flg.Value.(boolFlag)
+	bf, ok := unwrap(flg.Value).(boolFlag)
 	return ok && bf.IsBoolFlag()
 }
+
+// negatableFlag is a [flag.Value] that also implements a method IsNegatable, used to determine if the flag accepts a
+// negated '--no-' form.
+type negatableFlag interface {
+	flag.Value
+	IsNegatable() bool
+}
+
+// isNegatableFlag checks if the given flag has a [flag.Value] which is a negatable boolean flag.
+func isNegatableFlag(flg *flag.Flag) bool {
+	nf, ok := unwrap(flg.Value).(negatableFlag)
+	return ok && nf.IsNegatable() && len(flg.Name) > 1
+}
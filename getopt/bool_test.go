@@ -0,0 +1,86 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestNegatableBoolVar(t *testing.T) {
+	t.Run("should parse flag and negated flag", func(t *testing.T) {
+		testcases := []struct {
+			args     []string
+			def      bool
+			expected bool
+		}{
+			{args: []string{"--gpg-sign"}, def: false, expected: true},
+			{args: []string{"--no-gpg-sign"}, def: true, expected: false},
+			{args: []string{"--gpg-sign=false"}, def: true, expected: false},
+			{args: []string{"--no-gpg-sign", "--gpg-sign"}, def: false, expected: true},
+			{args: []string{"-S", "--no-gpg-sign"}, def: false, expected: false},
+			{args: []string{}, def: true, expected: true},
+		}
+
+		for _, tc := range testcases {
+			var sign bool
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.BoolVarN(&sign, "gpg-sign", tc.def, "sign commits")
+			Alias(fs.FlagSet, "gpg-sign", "S")
+
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sign != tc.expected {
+				t.Fatalf("unexpected value for args %v: %v", tc.args, sign)
+			}
+		}
+	})
+
+	t.Run("should reject negated flag with value", func(t *testing.T) {
+		var sign bool
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.BoolVarN(&sign, "gpg-sign", false, "sign commits")
+
+		if err := fs.Parse([]string{"--no-gpg-sign=true"}); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("should not negate short flags or regular bool flags", func(t *testing.T) {
+		var sign, all bool
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.BoolVarN(&sign, "s", false, "sign commits")
+		fs.BoolVar(&all, "all", false, "show all")
+
+		if err := fs.Parse([]string{"--no-s"}); err == nil {
+			t.Fatalf("expected error")
+		}
+		if err := fs.Parse([]string{"--no-all"}); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("should render negated form in usage", func(t *testing.T) {
+		var (
+			buf  bytes.Buffer
+			sign bool
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.BoolVarN(&sign, "gpg-sign", false, "sign commits")
+		Alias(fs.FlagSet, "gpg-sign", "S")
+
+		fs.PrintDefaults()
+
+		expected := "  -S, --gpg-sign, --no-gpg-sign\n      sign commits\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected usage string: '%s'", buf.String())
+		}
+	})
+}
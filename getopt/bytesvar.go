@@ -0,0 +1,123 @@
+package getopt
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// byteUnits are the units of [BytesVar] sizes. Each unit is rendered with its suffix, and given with any of its aliases
+// (ignoring case). IEC units are listed first so that [BytesVar.String] prefers them.
+var byteUnits = []struct {
+	suffix     string
+	aliases    []string
+	multiplier int64
+}{
+	{suffix: "Ti", aliases: []string{"ti", "tib"}, multiplier: 1 << 40},
+	{suffix: "Gi", aliases: []string{"gi", "gib"}, multiplier: 1 << 30},
+	{suffix: "Mi", aliases: []string{"mi", "mib"}, multiplier: 1 << 20},
+	{suffix: "Ki", aliases: []string{"ki", "kib"}, multiplier: 1 << 10},
+	{suffix: "T", aliases: []string{"t", "tb"}, multiplier: 1e12},
+	{suffix: "G", aliases: []string{"g", "gb"}, multiplier: 1e9},
+	{suffix: "M", aliases: []string{"m", "mb"}, multiplier: 1e6},
+	{suffix: "K", aliases: []string{"k", "kb"}, multiplier: 1e3},
+	{suffix: "", aliases: []string{"", "b"}, multiplier: 1},
+}
+
+// BytesVar is a [flag.Value] for flags that accept a number of bytes in a human-readable form. BytesVar also implements
+// [flag.Getter].
+//
+// Sizes are given as a (possibly fractional) number, optionally followed by an SI (powers of 1000) or IEC (powers of
+// 1024) unit suffix. Suffixes are case-insensitive and may end with 'B':
+//
+//	--max-body-size 512      // 512 bytes
+//	--max-body-size 64Mi     // 64 * 1024 * 1024 bytes
+//	--max-body-size 1GB      // 1000 * 1000 * 1000 bytes
+//	--max-body-size 1.5Ki    // 1536 bytes
+//
+// Negative sizes, unknown suffixes and sizes exceeding [math.MaxInt64] bytes are rejected.
+type BytesVar int64
+
+// Bytes returns a [BytesVar] for n.
+func Bytes(n *int64) *BytesVar {
+	return (*BytesVar)(n)
+}
+
+// BytesVar defines a [BytesVar] flag with the specified name, default value and usage string. The argument p points to
+// an int64 variable in which to store the number of bytes.
+func (f *PosixFlagSet) BytesVar(p *int64, name string, value int64, usage string) {
+	*p = value
+	f.Var(Bytes(p), name, usage)
+}
+
+// String returns the size in a compact form with the largest unit dividing it evenly (e.g. '64Mi' or '1500'), which
+// can be parsed by [BytesVar.Set].
+func (b BytesVar) String() string {
+	n := int64(b)
+
+	for _, unit := range byteUnits {
+		if n != 0 && n%unit.multiplier == 0 {
+			return strconv.FormatInt(n/unit.multiplier, 10) + unit.suffix
+		}
+	}
+
+	return strconv.FormatInt(n, 10)
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be a non-negative number of bytes with an optional
+// unit suffix.
+func (b *BytesVar) Set(value string) error {
+	idx := strings.LastIndexAny(value, "0123456789.") + 1
+	number, suffix := value[:idx], value[idx:]
+
+	for _, unit := range byteUnits {
+		if !slices.ContainsFunc(unit.aliases, func(alias string) bool { return strings.EqualFold(alias, suffix) }) {
+			continue
+		}
+
+		n, err := parseBytes(number, unit.multiplier)
+		if err != nil {
+			return fmt.Errorf("invalid size '%s': %w", value, err)
+		}
+
+		*b = BytesVar(n)
+		return nil
+	}
+
+	return fmt.Errorf("invalid size '%s': unknown unit suffix '%s'", value, suffix)
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns an int64.
+func (b *BytesVar) Get() any {
+	return int64(*b)
+}
+
+// parseBytes parses number and multiplies it by multiplier, rejecting negative and overflowing sizes.
+func parseBytes(number string, multiplier int64) (int64, error) {
+	if strings.HasPrefix(number, "-") {
+		return 0, fmt.Errorf("size must not be negative")
+	}
+
+	// integers are multiplied exactly
+	if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+		if n > math.MaxInt64/multiplier {
+			return 0, fmt.Errorf("size out of range")
+		}
+
+		return n * multiplier, nil
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("malformed number '%s'", number)
+	}
+
+	size := math.Round(f * float64(multiplier))
+	if size >= math.MaxInt64 {
+		return 0, fmt.Errorf("size out of range")
+	}
+
+	return int64(size), nil
+}
@@ -0,0 +1,111 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestBytesVar(t *testing.T) {
+	t.Run("should parse sizes", func(t *testing.T) {
+		testcases := map[string]int64{
+			"0":      0,
+			"512":    512,
+			"512B":   512,
+			"1K":     1000,
+			"1kb":    1000,
+			"1Ki":    1024,
+			"1.5Ki":  1536,
+			"64Mi":   64 << 20,
+			"64MiB":  64 << 20,
+			"1GB":    1e9,
+			"2gi":    2 << 30,
+			"1T":     1e12,
+			"1Ti":    1 << 40,
+			"0.5M":   500000,
+			"8Gi":    8 << 30,
+			"1000Mi": 1000 << 20,
+		}
+
+		for arg, expected := range testcases {
+			var size int64
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.BytesVar(&size, "max-size", 0, "max size")
+
+			if err := fs.Parse([]string{"--max-size", arg}); err != nil {
+				t.Fatalf("unexpected error for '%s': %v", arg, err)
+			}
+			if size != expected {
+				t.Fatalf("unexpected result for '%s': %d", arg, size)
+			}
+			if v, ok := fs.GetValue("max-size"); !ok || v != expected {
+				t.Fatalf("unexpected value: %v", v)
+			}
+		}
+	})
+
+	t.Run("should return error for invalid sizes", func(t *testing.T) {
+		testcases := map[string]string{
+			"-1":           "size must not be negative",
+			"-5Mi":         "size must not be negative",
+			"5Xi":          "unknown unit suffix 'Xi'",
+			"abc":          "unknown unit suffix 'abc'",
+			"Mi":           "malformed number ''",
+			"1.2.3K":       "malformed number '1.2.3'",
+			"9000000Ti":    "size out of range",
+			"9000000000Gi": "size out of range",
+		}
+
+		for arg, expected := range testcases {
+			var size BytesVar
+
+			err := size.Set(arg)
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Fatalf("unexpected error for '%s': %v", arg, err)
+			}
+		}
+	})
+
+	t.Run("should render compact sizes", func(t *testing.T) {
+		testcases := map[int64]string{
+			0:        "0",
+			512:      "512",
+			1500:     "1500",
+			2048:     "2Ki",
+			64 << 20: "64Mi",
+			1e6:      "1M",
+			3e9:      "3G",
+			1 << 40:  "1Ti",
+		}
+
+		for n, expected := range testcases {
+			if result := BytesVar(n).String(); result != expected {
+				t.Fatalf("unexpected result for %d: %s", n, result)
+			}
+
+			var size BytesVar
+			if err := size.Set(expected); err != nil || int64(size) != n {
+				t.Fatalf("rendered size '%s' does not round-trip: %d, %v", expected, size, err)
+			}
+		}
+	})
+
+	t.Run("should render usage", func(t *testing.T) {
+		var (
+			buf  bytes.Buffer
+			size int64
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.BytesVar(&size, "max-size", 64<<20, "max size")
+
+		fs.PrintDefaults()
+
+		if expected := "  --max-size=<size> (default 64Mi)\n      max size\n"; buf.String() != expected {
+			t.Fatalf("unexpected usage: %q", buf.String())
+		}
+	})
+}
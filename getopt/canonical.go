@@ -0,0 +1,35 @@
+package getopt
+
+import (
+	"flag"
+)
+
+// LookupCanonical returns the canonical flag for the flag with the given name. Flags sharing the same [flag.Value]
+// (see [Alias]) form a group, and the canonical flag is the flag in the group with the longest name. If multiple flags
+// have names of the same length, the lexicographically first is chosen. Returns nil if no such flag exists.
+//
+// This is useful to normalize flag names, for instance when serializing flag values to a configuration file:
+//
+//	getopt.Alias(fs.FlagSet, "verbose", "v")
+//	fs.LookupCanonical("v").Name   ->   "verbose"
+func (f *PosixFlagSet) LookupCanonical(name string) *flag.Flag {
+	flg := f.Lookup(name)
+	if flg == nil {
+		return nil
+	}
+
+	canonical := flg
+
+	f.VisitAll(func(other *flag.Flag) {
+		if !areSame(flg.Value, other.Value) {
+			return
+		}
+
+		if len(other.Name) > len(canonical.Name) ||
+			(len(other.Name) == len(canonical.Name) && other.Name < canonical.Name) {
+			canonical = other
+		}
+	})
+
+	return canonical
+}
@@ -0,0 +1,38 @@
+package getopt
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestLookupCanonical(t *testing.T) {
+	fs := NewPosixFlagSet("test", flag.ContinueOnError)
+	fs.Bool("v", false, "verbose output")
+	Alias(fs.FlagSet, "v", "verbose")
+	Alias(fs.FlagSet, "v", "vv")
+	fs.String("name", "", "name")
+	fs.String("output", "", "output file")
+	Alias(fs.FlagSet, "output", "o")
+	Alias(fs.FlagSet, "output", "target")
+
+	for name, expected := range map[string]string{
+		"v":       "verbose",
+		"vv":      "verbose",
+		"verbose": "verbose",
+		"name":    "name",
+		"o":       "output",
+		"target":  "output",
+	} {
+		flg := fs.LookupCanonical(name)
+		if flg == nil {
+			t.Fatalf("expected canonical flag for '%s'", name)
+		}
+		if flg.Name != expected {
+			t.Fatalf("unexpected canonical flag for '%s': %s", name, flg.Name)
+		}
+	}
+
+	if fs.LookupCanonical("missing") != nil {
+		t.Fatalf("expected nil for missing flag")
+	}
+}
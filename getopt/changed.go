@@ -0,0 +1,28 @@
+package getopt
+
+import (
+	"flag"
+)
+
+// Changed reports whether the flag with the given name was explicitly set at the command line, either directly or
+// through any of its aliases (see [Alias]). Returns false if the flag does not exist.
+//
+// This distinguishes a flag given at the command line from a flag holding its default value, which is useful when
+// merging flags with other configuration sources:
+//
+//	if fs.Changed("timeout") {
+//		cfg.Timeout = timeout
+//	}
+func (f *PosixFlagSet) Changed(name string) bool {
+	flg := f.Lookup(name)
+	if flg == nil {
+		return false
+	}
+
+	var set bool
+	f.Visit(func(other *flag.Flag) {
+		set = set || areSame(flg.Value, other.Value)
+	})
+
+	return set
+}
@@ -0,0 +1,36 @@
+package getopt
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestChanged(t *testing.T) {
+	var (
+		count int
+		name  string
+	)
+
+	fs := NewPosixFlagSet("test", flag.ContinueOnError)
+	fs.IntVar(&count, "count", 12, "count")
+	fs.StringVar(&name, "name", "default", "name")
+	Alias(fs.FlagSet, "count", "c")
+
+	if fs.Changed("count") || fs.Changed("c") || fs.Changed("name") {
+		t.Fatalf("expected flags to be unchanged before parsing")
+	}
+
+	if err := fs.Parse([]string{"-c", "12"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !fs.Changed("count") || !fs.Changed("c") {
+		t.Fatalf("expected flag set through alias to be changed")
+	}
+	if fs.Changed("name") {
+		t.Fatalf("expected flag 'name' to be unchanged")
+	}
+	if fs.Changed("missing") {
+		t.Fatalf("expected missing flag to be unchanged")
+	}
+}
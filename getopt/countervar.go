@@ -28,6 +28,15 @@ func Counter[T CounterType](value *T) *CounterVar[T] {
 	}
 }
 
+// CountVar defines a [CounterVar] flag with the specified name and usage string. The argument p points to an int
+// variable which is incremented every time the flag appears at the command line. The default value of the flag is the
+// current value of p.
+//
+//	-v -v -v    // equivalent to '-vvv', p is incremented by 3
+func (f *PosixFlagSet) CountVar(p *int, name string, usage string) {
+	f.Var(Counter(p), name, usage)
+}
+
 // String returns the value of the counter as a string.
 func (c *CounterVar[T]) String() string {
 	var zero T
@@ -57,9 +66,15 @@ func (c *CounterVar[T]) Set(value string) error {
 	return nil
 }
 
-// Get returns the counter value as an integer.
+// Get returns the counter value as an integer of type T.
 func (c *CounterVar[T]) Get() any {
-	return c.value
+	var zero T
+
+	if c == nil || c.value == nil {
+		return zero
+	}
+
+	return *c.value
 }
 
 // IsBoolFlag marks the flag as being a boolean.
@@ -1,6 +1,7 @@
 package getopt
 
 import (
+	"bytes"
 	"flag"
 	"testing"
 )
@@ -23,6 +24,30 @@ func TestCounterVar(t *testing.T) {
 		}
 	})
 
+	t.Run("should register counter flag with CountVar", func(t *testing.T) {
+		var (
+			buf       bytes.Buffer
+			verbosity int
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.CountVar(&verbosity, "v", "increase `verbosity`")
+
+		if err := fs.Parse([]string{"-vvv", "-v", "-v"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if verbosity != 5 {
+			t.Fatalf("unexpected counter value: %d", verbosity)
+		}
+
+		fs.PrintDefaults()
+
+		if expected := "  -v\n      increase verbosity\n"; buf.String() != expected {
+			t.Fatalf("unexpected usage string: '%s'", buf.String())
+		}
+	})
+
 	t.Run("should panic if nil", func(t *testing.T) {
 		defer func() {
 			if r := recover(); r == nil {
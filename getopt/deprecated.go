@@ -0,0 +1,97 @@
+package getopt
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Deprecate marks the flag with the given name as deprecated. Whenever the flag is set during [PosixFlagSet.Parse], a
+// warning containing message is written to the output configured by [flag.FlagSet.SetOutput]. The flag continues to
+// work as before.
+//
+//	fs.StringVar(&output, "output", "-", "output `file`")
+//	getopt.Alias(fs.FlagSet, "output", "out")
+//	fs.Deprecate("out", "use --output instead")
+//
+// Only the given flag name is deprecated, not its aliases, allowing old flag names to be phased out in favour of new
+// ones. To also remove the flag from usage text, see [Hide].
+//
+// If flag name doesn't exist in f, panic.
+func (f *PosixFlagSet) Deprecate(name, message string) {
+	if f.Lookup(name) == nil {
+		panic(fmt.Sprintf("getopt: cannot deprecate flag '%s': flag does not exist in flag set", name))
+	}
+
+	if f.deprecated == nil {
+		f.deprecated = map[string]string{}
+	}
+
+	f.deprecated[name] = message
+}
+
+// MarkValueDeprecated marks a specific value of the flag with the given name as deprecated. When the flag is set to
+// value during [PosixFlagSet.Parse], a warning containing message is written to the output configured by
+// [flag.FlagSet.SetOutput]. The value is still accepted.
+//
+//	fs.StringVar(&format, "format", "short", "output `format` (short, long, legacy)")
+//	fs.MarkValueDeprecated("format", "legacy", "use --format=long instead")
+//
+// Deprecated values also apply to aliases of the flag (see [Alias]).
+//
+// If flag name doesn't exist in f, panic.
+func (f *PosixFlagSet) MarkValueDeprecated(name, value, message string) {
+	if f.Lookup(name) == nil {
+		panic(fmt.Sprintf("getopt: cannot deprecate value of flag '%s': flag does not exist in flag set", name))
+	}
+
+	if f.deprecatedValues == nil {
+		f.deprecatedValues = map[string]map[string]string{}
+	}
+	if f.deprecatedValues[name] == nil {
+		f.deprecatedValues[name] = map[string]string{}
+	}
+
+	f.deprecatedValues[name][value] = message
+}
+
+// warnDeprecatedValue writes a warning to the flag set output if value is a deprecated value of the flag name (or any
+// of its aliases).
+func (f *PosixFlagSet) warnDeprecatedValue(name, value string) {
+	flg := f.Lookup(name)
+	if flg == nil {
+		return
+	}
+
+	for target, values := range f.deprecatedValues {
+		message, ok := values[value]
+		if !ok {
+			continue
+		}
+
+		if tflg := f.Lookup(target); tflg == nil || !areSame(flg.Value, tflg.Value) {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(f.Output(), "warning: value '%s' of flag '%s' is deprecated: %s\n", value, display(flg),
+			message)
+
+		return
+	}
+}
+
+// warnDeprecated writes a warning to the flag set output if the flag name is deprecated.
+func (f *PosixFlagSet) warnDeprecated(name string) {
+	if message, ok := f.deprecated[name]; ok {
+		_, _ = fmt.Fprintf(f.Output(), "warning: flag '%s' is deprecated: %s\n", display(f.Lookup(name)), message)
+	}
+}
+
+// display returns the name of flg as it would be given at the command line ('-a' for short flags, '--all' for long
+// flags).
+func display(flg *flag.Flag) string {
+	if len(flg.Name) == 1 {
+		return "-" + flg.Name
+	}
+
+	return "--" + flg.Name
+}
@@ -0,0 +1,141 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestMarkValueDeprecated(t *testing.T) {
+	t.Run("should panic if flag does not exist", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("no panic")
+			}
+		}()
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.MarkValueDeprecated("format", "legacy", "use --format=long instead")
+	})
+
+	t.Run("should emit warning only for deprecated value", func(t *testing.T) {
+		var (
+			buf    bytes.Buffer
+			format string
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.StringVar(&format, "format", "short", "output format")
+		fs.MarkValueDeprecated("format", "legacy", "use --format=long instead")
+
+		if err := fs.Parse([]string{"--format", "long"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("unexpected warning: '%s'", buf.String())
+		}
+
+		if err := fs.Parse([]string{"--format=legacy"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if format != "legacy" {
+			t.Fatalf("format var not updated with expected value: %s", format)
+		}
+
+		expected := "warning: value 'legacy' of flag '--format' is deprecated: use --format=long instead\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected warning: '%s'", buf.String())
+		}
+	})
+
+	t.Run("should emit warning for aliases", func(t *testing.T) {
+		var (
+			buf    bytes.Buffer
+			format string
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.StringVar(&format, "format", "short", "output format")
+		Alias(fs.FlagSet, "format", "f")
+		fs.MarkValueDeprecated("format", "legacy", "use -f long instead")
+
+		if err := fs.Parse([]string{"-flegacy"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "warning: value 'legacy' of flag '-f' is deprecated: use -f long instead\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected warning: '%s'", buf.String())
+		}
+	})
+}
+
+func TestDeprecate(t *testing.T) {
+	t.Run("should panic if flag does not exist", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("no panic")
+			}
+		}()
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Deprecate("out", "use --output instead")
+	})
+
+	t.Run("should emit warning only when deprecated flag is used", func(t *testing.T) {
+		var (
+			buf    bytes.Buffer
+			output string
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.StringVar(&output, "output", "-", "output file")
+		Alias(fs.FlagSet, "output", "out")
+		fs.Deprecate("out", "use --output instead")
+
+		if err := fs.Parse([]string{"--output", "a.txt"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if buf.Len() != 0 {
+			t.Fatalf("unexpected warning: '%s'", buf.String())
+		}
+
+		if err := fs.Parse([]string{"--out=b.txt"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if output != "b.txt" {
+			t.Fatalf("output var not updated with expected value: %s", output)
+		}
+
+		expected := "warning: flag '--out' is deprecated: use --output instead\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected warning: '%s'", buf.String())
+		}
+	})
+
+	t.Run("should not interfere with parse errors", func(t *testing.T) {
+		var (
+			buf   bytes.Buffer
+			count int
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.Usage = func() {}
+		fs.IntVar(&count, "c", 0, "count")
+		fs.Deprecate("c", "use --count instead")
+
+		err := fs.Parse([]string{"-cX"})
+		if err == nil || err.Error() != "invalid value 'X' for flag '-c': parse error" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "warning: flag '-c' is deprecated: use --count instead\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected warning: '%s'", buf.String())
+		}
+	})
+}
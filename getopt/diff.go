@@ -0,0 +1,41 @@
+package getopt
+
+import (
+	"flag"
+)
+
+// Diff compares the values of the flags in flag sets a and b, returning the flags whose values differ keyed by flag
+// name. Each entry holds the value in a followed by the value in b. Flags defined in only one of the flag sets are
+// compared against an empty value.
+//
+// Diff is useful when reloading configuration, for instance to log which flags changed:
+//
+//	for name, values := range getopt.Diff(old, new) {
+//		log.Printf("changed: %s old=%s new=%s", name, values[0], values[1])
+//	}
+func Diff(a, b *flag.FlagSet) map[string][2]string {
+	diff := map[string][2]string{}
+
+	a.VisitAll(func(flg *flag.Flag) {
+		var other string
+		if o := b.Lookup(flg.Name); o != nil {
+			other = o.Value.String()
+		}
+
+		if value := flg.Value.String(); value != other {
+			diff[flg.Name] = [2]string{value, other}
+		}
+	})
+
+	b.VisitAll(func(flg *flag.Flag) {
+		if a.Lookup(flg.Name) != nil {
+			return
+		}
+
+		if value := flg.Value.String(); value != "" {
+			diff[flg.Name] = [2]string{"", value}
+		}
+	})
+
+	return diff
+}
@@ -0,0 +1,56 @@
+package getopt
+
+import (
+	"flag"
+	"maps"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	fs := func(level string, workers int) *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.String("log-level", level, "log level")
+		fs.Int("workers", workers, "worker count")
+		fs.Bool("verbose", false, "verbose")
+		return fs
+	}
+
+	t.Run("should return flags with differing values", func(t *testing.T) {
+		a, b := fs("info", 4), fs("debug", 4)
+
+		if err := b.Parse([]string{"-verbose"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := map[string][2]string{
+			"log-level": {"info", "debug"},
+			"verbose":   {"false", "true"},
+		}
+
+		if diff := Diff(a, b); !maps.Equal(expected, diff) {
+			t.Fatalf("unexpected diff: %v", diff)
+		}
+	})
+
+	t.Run("should return empty map for identical flag sets", func(t *testing.T) {
+		if diff := Diff(fs("info", 4), fs("info", 4)); len(diff) != 0 {
+			t.Fatalf("unexpected diff: %v", diff)
+		}
+	})
+
+	t.Run("should compare flags defined in one flag set against empty value", func(t *testing.T) {
+		a, b := fs("info", 4), fs("info", 4)
+		a.String("only-a", "x", "")
+		b.String("only-b", "y", "")
+		b.String("only-b-empty", "", "")
+
+		expected := map[string][2]string{
+			"only-a": {"x", ""},
+			"only-b": {"", "y"},
+		}
+
+		if diff := Diff(a, b); !maps.Equal(expected, diff) {
+			t.Fatalf("unexpected diff: %v", diff)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package getopt
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// EnumVar is a [flag.Value] for flags that accept one of a fixed set of string values. EnumVar also implements
+// [flag.Getter].
+//
+// Values not in the allowed set are rejected with an error listing the permitted values. Values are matched
+// case-sensitively unless IgnoreCase is set, in which case the flag is updated with the matching allowed value.
+//
+// Unless the flag usage names the flag argument explicitly (see [flag.UnquoteUsage]), [PosixFlagSet.PrintDefaults]
+// renders the allowed values as the argument placeholder:
+//
+//	--cascade=<background|orphan|foreground> (default background)
+//
+// To initialize an EnumVar, see [Enum] or [PosixFlagSet.EnumVar].
+type EnumVar struct {
+	// If true, values are matched case-insensitively.
+	IgnoreCase bool
+
+	value   *string
+	allowed []string
+}
+
+// Enum returns an [EnumVar] for s, accepting only the values in allowed.
+func Enum(s *string, allowed ...string) *EnumVar {
+	return &EnumVar{
+		value:   s,
+		allowed: allowed,
+	}
+}
+
+// EnumVar defines an [EnumVar] flag with the specified name, allowed values, default value and usage string. The
+// argument p points to a string variable in which to store the value of the flag. Values are matched
+// case-sensitively.
+func (f *PosixFlagSet) EnumVar(p *string, name string, allowed []string, value string, usage string) {
+	*p = value
+	f.Var(Enum(p, allowed...), name, usage)
+}
+
+// String returns the value of the flag.
+func (e *EnumVar) String() string {
+	if e == nil || e.value == nil {
+		return ""
+	}
+
+	return *e.value
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be one of the allowed values.
+func (e *EnumVar) Set(value string) error {
+	idx := slices.IndexFunc(e.allowed, func(allowed string) bool {
+		if e.IgnoreCase {
+			return strings.EqualFold(allowed, value)
+		}
+
+		return allowed == value
+	})
+
+	if idx < 0 {
+		return fmt.Errorf("getopt: value must be one of: %s", strings.Join(e.allowed, ", "))
+	}
+
+	*e.value = e.allowed[idx]
+
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a string.
+func (e *EnumVar) Get() any {
+	return *e.value
+}
+
+// Validate fulfills the [Validator] interface, checking that the current value is one of the allowed values.
+func (e *EnumVar) Validate() error {
+	if !slices.Contains(e.allowed, *e.value) {
+		return fmt.Errorf("getopt: value must be one of: %s", strings.Join(e.allowed, ", "))
+	}
+
+	return nil
+}
+
+// Allowed returns the values accepted by the flag.
+func (e *EnumVar) Allowed() []string {
+	return slices.Clone(e.allowed)
+}
+
+// enumFlag is a [flag.Value] that also implements a method Allowed, used to render the set of accepted values.
+type enumFlag interface {
+	flag.Value
+	Allowed() []string
+}
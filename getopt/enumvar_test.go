@@ -0,0 +1,90 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestEnumVar(t *testing.T) {
+	allowed := []string{"background", "orphan", "foreground"}
+
+	t.Run("should accept allowed values", func(t *testing.T) {
+		var cascade string
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.EnumVar(&cascade, "cascade", allowed, "background", "deletion cascading strategy")
+
+		if cascade != "background" {
+			t.Fatalf("cascade var not updated with expected default value: %s", cascade)
+		}
+
+		if err := fs.Parse([]string{"--cascade", "orphan"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cascade != "orphan" {
+			t.Fatalf("cascade var not updated with expected value: %s", cascade)
+		}
+	})
+
+	t.Run("should reject values not allowed", func(t *testing.T) {
+		var cascade string
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.EnumVar(&cascade, "cascade", allowed, "background", "deletion cascading strategy")
+
+		err := fs.Parse([]string{"--cascade=Orphan"})
+		if err == nil || !strings.Contains(err.Error(), "must be one of: background, orphan, foreground") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cascade != "background" {
+			t.Fatalf("cascade var unexpectedly updated: %s", cascade)
+		}
+	})
+
+	t.Run("should match case-insensitively if configured", func(t *testing.T) {
+		var cascade string
+
+		e := Enum(&cascade, allowed...)
+		e.IgnoreCase = true
+
+		if err := e.Set("ORPHAN"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cascade != "orphan" {
+			t.Fatalf("cascade var not updated with expected value: %s", cascade)
+		}
+	})
+
+	t.Run("should render allowed values as placeholder", func(t *testing.T) {
+		var (
+			buf             bytes.Buffer
+			cascade, output string
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.EnumVar(&cascade, "cascade", allowed, "background", "deletion cascading strategy")
+		fs.EnumVar(&output, "o", []string{"json", "yaml"}, "", "output `format`")
+
+		fs.PrintDefaults()
+
+		expected := "  --cascade=<background|orphan|foreground> (default background)\n" +
+			"      deletion cascading strategy\n\n" +
+			"  -o <format>\n" +
+			"      output format\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected usage string: '%s'", buf.String())
+		}
+	})
+
+	t.Run("should not panic if calling String on nil value", func(t *testing.T) {
+		var z *EnumVar
+
+		if result := z.String(); result != "" {
+			t.Fatalf("unexpected result: %s", result)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package getopt_test
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/brandon1024/cmder/getopt"
+)
+
+// This example demonstrates the usage of [getopt.IntsVar] for flags which accept multiple integer values.
+func ExampleIntsVar() {
+	var ports []int
+
+	fs := getopt.NewPosixFlagSet("ints", flag.ContinueOnError)
+	fs.Var(getopt.Ints(&ports), "port", "listen on `port`")
+
+	if err := fs.Parse([]string{"--port", "80,443", "--port", "8080"}); err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("ports: %v\n", ports)
+	// Output:
+	// ports: [80 443 8080]
+}
@@ -0,0 +1,85 @@
+package getopt
+
+import (
+	"io"
+	"os"
+)
+
+// FileVar is a [flag.Value] for flags that name an input file. Following the common convention, the value '-' reads
+// from standard input. FileVar also implements [flag.Getter].
+//
+// The file is opened when the flag is set, so errors like missing files are reported when parsing flags. The zero
+// value is ready to use:
+//
+//	var input getopt.FileVar
+//	fs.Var(&input, "input", "read records from `file` ('-' for stdin)")
+//
+//	// ...
+//
+//	defer input.Close()
+//	records, err := io.ReadAll(input.Reader())
+//
+// Files opened by the flag must be closed with Close. Standard input is never closed.
+type FileVar struct {
+	// Name is the name of the file given to the flag, or '-' for standard input.
+	Name string
+
+	reader io.Reader
+	closer io.Closer
+}
+
+// String returns the name of the file given to the flag.
+func (f FileVar) String() string {
+	return f.Name
+}
+
+// Set fulfills the [flag.Value] interface. The given value is the name of a file to open for reading, or '-' for
+// standard input. A file previously opened by the flag is closed.
+func (f *FileVar) Set(value string) error {
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if value == "-" {
+		f.Name, f.reader = value, os.Stdin
+		return nil
+	}
+
+	file, err := os.Open(value)
+	if err != nil {
+		return err
+	}
+
+	f.Name, f.reader, f.closer = value, file, file
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns an
+// [io.Reader] (see [FileVar.Reader]).
+func (f *FileVar) Get() any {
+	return f.Reader()
+}
+
+// Reader returns a reader for the file given to the flag, [os.Stdin] if the flag was set to '-', or nil if the flag was
+// not set.
+func (f *FileVar) Reader() io.Reader {
+	return f.reader
+}
+
+// Close closes the file opened by the flag, if any. Close is a no-op if the flag was not set or reads from standard
+// input.
+func (f *FileVar) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+
+	err := f.closer.Close()
+	f.reader, f.closer = nil, nil
+	return err
+}
+
+// Validate fulfills the [Validator] interface. The file is opened when the flag is set, so there is nothing left to
+// check.
+func (f *FileVar) Validate() error {
+	return nil
+}
@@ -0,0 +1,95 @@
+package getopt
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileVar(t *testing.T) {
+	t.Run("should open named file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "input.txt")
+		if err := os.WriteFile(path, []byte("hello"), 0o600); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var input FileVar
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Var(&input, "input", "input file")
+
+		if err := fs.Parse([]string{"--input", path}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if input.String() != path {
+			t.Fatalf("unexpected name: %s", input.String())
+		}
+
+		data, err := io.ReadAll(input.Reader())
+		if err != nil || string(data) != "hello" {
+			t.Fatalf("unexpected result: %q (%v)", data, err)
+		}
+
+		if err := input.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if input.Reader() != nil {
+			t.Fatalf("expected reader to be released after close")
+		}
+	})
+
+	t.Run("should read from stdin given '-'", func(t *testing.T) {
+		var input FileVar
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Var(&input, "input", "input file")
+
+		if err := fs.Parse([]string{"--input", "-"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v, ok := fs.GetValue("input"); !ok || v != io.Reader(os.Stdin) {
+			t.Fatalf("unexpected value: %v", v)
+		}
+		if err := input.Close(); err != nil || input.Reader() != os.Stdin {
+			t.Fatalf("stdin should not be closed: %v", err)
+		}
+	})
+
+	t.Run("should return error for missing file", func(t *testing.T) {
+		var input FileVar
+
+		flags := NewPosixFlagSet("test", flag.ContinueOnError)
+		flags.Usage = func() {}
+		flags.Var(&input, "input", "input file")
+
+		err := flags.Parse([]string{"--input", filepath.Join(t.TempDir(), "missing")})
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if input.Reader() != nil {
+			t.Fatalf("unexpected reader: %v", input.Reader())
+		}
+	})
+
+	t.Run("should render placeholder in usage", func(t *testing.T) {
+		var (
+			buf   bytes.Buffer
+			input FileVar
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.Var(&input, "input", "input file")
+		fs.PrintDefaults()
+
+		if !strings.Contains(buf.String(), "--input=<file>\n") {
+			t.Fatalf("unexpected usage:\n%s", buf.String())
+		}
+	})
+}
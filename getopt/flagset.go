@@ -60,11 +60,21 @@ import (
 //	--count 12  // long integer value
 //	--count=12  // long integer value with immediate value
 //
+// Flags accepting an argument take the next argument verbatim, even if it looks like a flag ('--format --json' sets
+// '--format' to '--json'). Immediate values are never interpreted as flags, so values beginning with a hyphen are best
+// given inline for clarity:
+//
+//	--format=--json
+//	-f--json
+//
 // Short boolean flags may be combined into a single argument, and short flags accepting arguments may be "stuck" to the
 // value:
 //
 //	-ac12       // equivalent to '-a -c 12'
 //
+// A short flag accepting an argument consumes the remainder of the argument as its value, so '-c12a' sets '-c' to
+// '12a' even if '-a' is a boolean flag. To reject such ambiguous arguments, see StrictShortClusters.
+//
 // Flag parsing stops just before the first non-flag argument ("-" is a non-flag argument) or after the terminator "--".
 //
 // Flags which accept a number ([PosixFlagSet.Int], [PosixFlagSet.Uint], [PosixFlagSet.Float64], etc) will parse their arguments with
@@ -98,8 +108,86 @@ type PosixFlagSet struct {
 	// error will still be emitted if the input is ambiguous (e.g. '--auto' for '--auto-gc' or '--auto-maintenance').
 	RelaxedParsing bool
 
-	parsed bool
-	args   []string
+	// If true, [PosixFlagSet.PrintDefaults] renders the long description of flags which have one (see
+	// [PosixFlagSet.VarLong]) instead of the flag usage string.
+	PrintLongUsage bool
+
+	// If positive, [PosixFlagSet.PrintDefaults] truncates default values longer than MaxDefaultDisplayLen characters,
+	// marking the truncation with an ellipsis ('…'). The default value of the flag itself is unaffected. If zero,
+	// default values are never truncated.
+	MaxDefaultDisplayLen int
+
+	// If positive, [PosixFlagSet.PrintDefaults] word-wraps flag usage text so that lines don't exceed UsageWidth
+	// characters (e.g. 80), including indentation. Words longer than the available width are not broken. If zero, usage
+	// text is never wrapped.
+	UsageWidth int
+
+	// If non-nil, UnknownFlagHandler is invoked when Parse encounters an unknown flag instead of failing, and parsing
+	// continues. The handler is given the flag as it appears at the command line without leading hyphens: the name of
+	// unknown short flags (e.g. 'X' for '-abX'), or the name and any inline value of unknown long flags (e.g.
+	// 'name=value' for '--name=value'). Arguments following an unknown flag are never consumed. If the handler returns
+	// an error, parsing is aborted.
+	UnknownFlagHandler func(name string) error
+
+	// If true, Parse replaces arguments of the form '@file' with the arguments read from the named response file. See
+	// [PosixFlagSet.Parse] for details.
+	ResponseFiles bool
+
+	// If true, Parse writes a warning to the flag set output for every unknown flag and otherwise ignores it, which is
+	// useful for forward-compatibility (e.g. older binaries ignoring newer flags). Since it cannot be known whether an
+	// unknown flag accepts an argument, arguments following an unknown flag are never consumed: '--since 1d' leaves
+	// '1d' to be parsed as a regular argument, while '--since=1d' is ignored as a whole. Ignored if UnknownFlagHandler
+	// is set.
+	WarnUnknown bool
+
+	// If true, Parse accepts long flags given with a single hyphen (e.g. '-verbose' or '-output=file'), as done by the
+	// standard [flag] package. Arguments with a single hyphen are looked up as long flags first, and only parsed as
+	// combined short flags (e.g. '-vv') if no long flag by that name exists. Partial matches (see RelaxedParsing) are not
+	// considered for single-hyphen arguments.
+	AllowSingleDashLong bool
+
+	// If true, environment variable references ('$VAR' or '${VAR}') in values of string flags are expanded with
+	// [os.ExpandEnv] when parsed. References to unset variables are replaced with an empty string. String flags are
+	// flags whose [flag.Getter] returns a string or []string (e.g. flags registered with [flag.FlagSet.String] or
+	// [StringsVar]). Useful when arguments are not given through a shell (e.g. response files).
+	ExpandEnv bool
+
+	// If true, Parse rejects short flags stuck to a value ending with the names of known short boolean flags, since the
+	// user may have intended to combine them (e.g. '-c12a' where '-a' is a boolean flag). Such values must be given as
+	// a separate argument instead (e.g. '-a -c 12' or '-c 12a').
+	StrictShortClusters bool
+
+	// If true, Parse continues past unknown flags and returns a single error listing every unknown flag once all
+	// arguments are processed. Known flags are still parsed. Ignored if UnknownFlagHandler or WarnUnknown is set.
+	ContinueOnUnknown bool
+
+	// If true, Parse doesn't check the requirements of flags (see [MarkRequired]). This is useful when arguments are
+	// parsed with several calls to Parse (e.g. to intersperse flags with positional arguments), in which case
+	// requirements are checked with [PosixFlagSet.CheckRequired] once all arguments are parsed.
+	DeferRequired bool
+
+	parsed   bool
+	args     []string
+	consumed []string
+	unknown  []error
+
+	// describes where arguments given to Parse came from, keyed by index (see SetArgSource)
+	argSource func(int) string
+
+	// deprecation messages of deprecated flags, keyed by flag name
+	deprecated map[string]string
+
+	// deprecated flag values, keyed by flag name and value
+	deprecatedValues map[string]map[string]string
+
+	// value normalization functions, keyed by flag name
+	normalizers map[string]func(string) string
+
+	// number of times each flag was set while parsing, keyed by flag name
+	occurrences map[string]int
+
+	// whether the flag set is read-only (see Freeze)
+	frozen bool
 }
 
 // NewPosixFlagSet builds a new [flag.FlagSet] and wraps it with a [PosixFlagSet].
@@ -121,6 +209,16 @@ func NewPosixFlagSet(name string, e flag.ErrorHandling) *PosixFlagSet {
 //	-a <string>, --addr=<string>
 //	-s <string>, --serial-number=<string>
 //
+// Negatable boolean flags (see [PosixFlagSet.BoolVarN]) are rendered with their negated form:
+//
+//	--gpg-sign, --no-gpg-sign
+//
+// Default values are rendered unless they are the zero value of the flag type, or the flag is marked with [NoDefault].
+// Long default values may be truncated with MaxDefaultDisplayLen. Long usage text may be wrapped with UsageWidth.
+//
+// If PrintLongUsage is set, the long description of flags registered with [PosixFlagSet.VarLong] is rendered instead of
+// the flag usage string.
+//
 // Hidden flags, created with [Hide], are omitted from the output.
 func (f *PosixFlagSet) PrintDefaults() {
 	format := `
@@ -141,6 +239,8 @@ func (f *PosixFlagSet) PrintDefaults() {
 
 				{{- if (eq (len $flg.Name) 1) -}}
 					{{- printf "-%s" .Name -}}
+				{{- else if (negatable $flg) -}}
+					{{- printf "--%s, --no-%s" .Name .Name -}}
 				{{- else -}}
 					{{- printf "--%s" .Name -}}
 				{{- end -}}
@@ -155,19 +255,25 @@ func (f *PosixFlagSet) PrintDefaults() {
 				{{- end -}}
 			{{- end -}}
 
-			{{ if (not (zero (index . 0))) }}
-				{{- printf " (default %s)" (index . 0).DefValue -}}
+			{{ if (not (or (nodefault (index . 0)) (zero (index . 0)))) }}
+				{{- printf " (default %s)" (default (index . 0)) -}}
 			{{- end -}}
 
 			{{- println -}}
 
-			{{- printf "      %s\n" (index (unquote (index . 0)) 1) -}}
+			{{- range (describe (index . 0)) -}}
+				{{- printf "      %s\n" . -}}
+			{{- end -}}
 		{{- end -}}`
 
 	tmpl, err := template.New("usage").Funcs(template.FuncMap{
-		"unquote": unquote,
-		"zero":    zero,
-		"bool":    isBoolFlag,
+		"unquote":   unquote,
+		"zero":      zero,
+		"bool":      isBoolFlag,
+		"negatable": isNegatableFlag,
+		"describe":  f.describe,
+		"nodefault": isNoDefaultFlag,
+		"default":   f.displayDefault,
 	}).Parse(format)
 	if err != nil {
 		panic(err)
@@ -199,6 +305,13 @@ func (f *PosixFlagSet) Args() []string {
 	return f.args
 }
 
+// ConsumedArgs returns the arguments consumed by [PosixFlagSet.Parse]: flags, their values and any '--' terminator.
+// Together with [PosixFlagSet.Args], this partitions the arguments given to Parse. Arguments read from response files
+// appear in place of the '@file' argument. Returns nil if [PosixFlagSet.Parse] was not called or failed.
+func (f *PosixFlagSet) ConsumedArgs() []string {
+	return f.consumed
+}
+
 // Parsed returns whether or not [PosixFlagSet.Parse] has been invoked on this flag set.
 func (f *PosixFlagSet) Parsed() bool {
 	return f.parsed
@@ -208,14 +321,50 @@ func (f *PosixFlagSet) Parsed() bool {
 // the command name. Parse should only be called after all flags have been registered and before flags are accessed by
 // the application.
 //
-// The return value will be [flag.ErrHelp] if -help or -h were set but not defined.
+// The return value will be [flag.ErrHelp] if -help or -h were set but not defined. If any flag marked with
+// [MarkRequired] was not set, an error is returned.
+//
+// # Response Files
+//
+// If [PosixFlagSet] ResponseFiles is enabled, an argument '@file' in place of a flag is replaced with the arguments read
+// from the named file. Arguments in the file are separated by whitespace (including newlines) and may be quoted with
+// single or double quotes. Response files may reference other response files, up to a nesting depth of 10. An error
+// is returned if a response file cannot be read.
+//
+//	$ cat args.txt
+//	--output "my file.txt"
+//	-v
+//	$ prog @args.txt input.txt
+//
+// # Parse Errors
+//
+// Errors caused by a specific argument are returned as a [*ParseError] describing the argument. Errors caused by
+// arguments read from response files are prefixed with the file and line the argument was read from (e.g.
+// 'args.txt:2: flag '--outptu' does not exist'). See also [PosixFlagSet.SetArgSource].
 func (f *PosixFlagSet) Parse(arguments []string) error {
+	f.warnFrozen("Parse")
+
 	usage := f.Usage
 	if usage == nil {
 		usage = f.defaultUsage
 	}
 
+	f.unknown, f.consumed = nil, nil
+
+	// occurrences are counted across calls to Parse, like flags set (see DeferRequired)
+	if f.occurrences == nil {
+		f.occurrences = map[string]int{}
+	}
+
 	err := f.parse(arguments)
+	if err != nil {
+		f.consumed = nil
+	}
+
+	err = errors.Join(append(f.unknown, err)...)
+	if err == nil && !f.DeferRequired {
+		err = f.CheckRequired()
+	}
 	if err == nil {
 		return nil
 	}
@@ -246,6 +395,16 @@ func (f *PosixFlagSet) parse(arguments []string) error {
 
 	f.parsed = true
 
+	// track where each argument came from, for error reporting
+	origins := make([]argOrigin, len(arguments))
+	for i := range origins {
+		origins[i] = argOrigin{index: i}
+
+		if f.argSource != nil {
+			origins[i].source = f.argSource(i)
+		}
+	}
+
 	for len(arguments) > 0 {
 		arg := arguments[0]
 
@@ -257,50 +416,120 @@ func (f *PosixFlagSet) parse(arguments []string) error {
 
 		// double hyphens is sentinel and denotes end of arguments -- remove from arguments and return
 		if arg == "--" {
+			f.consumed = append(f.consumed, arg)
 			f.args = arguments[1:]
 			return nil
 		}
 
-		// parse long option
-		long, ok := strings.CutPrefix(arg, "--")
-		if ok {
-			arguments, err = f.parseLong(long, arguments[1:])
+		// response files are replaced with the arguments they contain
+		if path, ok := strings.CutPrefix(arg, "@"); ok && path != "" && f.ResponseFiles {
+			expanded, sources, err := readResponseFile(path, maxResponseFileDepth)
 			if err != nil {
-				return err
+				return origins[0].error(arg, err)
+			}
+
+			expandedOrigins := make([]argOrigin, len(expanded))
+			for i := range expandedOrigins {
+				expandedOrigins[i] = argOrigin{index: origins[0].index, source: sources[i]}
 			}
 
+			arguments = append(expanded, arguments[1:]...)
+			origins = append(expandedOrigins, origins[1:]...)
 			continue
 		}
 
-		// parse short option
-		short, ok := strings.CutPrefix(arg, "-")
-		if ok {
+		var (
+			previous = arguments
+			unknown  = len(f.unknown)
+		)
+
+		long, isLong := strings.CutPrefix(arg, "--")
+		short, isShort := strings.CutPrefix(arg, "-")
+
+		switch {
+		case isLong:
+			// parse long option
+			arguments, err = f.parseLong(long, arguments[1:])
+		case isShort && f.isSingleDashLong(short):
+			// parse long option given with a single hyphen (if applicable)
+			arguments, err = f.parseLong(short, arguments[1:])
+		case isShort:
+			// parse short option
 			arguments, err = f.parseShort(short, arguments[1:])
-			if err != nil {
-				return err
-			}
+		default:
+			f.args = arguments
+			return nil
+		}
 
-			continue
+		// describe unknown flags collected while parsing this argument (see ContinueOnUnknown)
+		for i := unknown; i < len(f.unknown); i++ {
+			f.unknown[i] = origins[0].error(arg, f.unknown[i])
 		}
 
-		f.args = arguments
-		return nil
+		if err != nil {
+			return origins[0].error(arg, err)
+		}
+
+		f.consumed = append(f.consumed, previous[:len(previous)-len(arguments)]...)
+		origins = origins[len(origins)-len(arguments):]
 	}
 
 	f.args = arguments
 	return nil
 }
 
+// isSingleDashLong checks if the argument short (without the leading hyphen) names a long flag given with a single
+// hyphen (e.g. '-verbose' or '-output=file'). Always false unless AllowSingleDashLong is enabled.
+func (f *PosixFlagSet) isSingleDashLong(short string) bool {
+	name, _, _ := strings.Cut(short, "=")
+
+	return f.AllowSingleDashLong && len([]rune(name)) > 1 && f.lookupLong(name, false) != nil
+}
+
 func (f *PosixFlagSet) parseLong(arg string, arguments []string) ([]string, error) {
 	arg, value, inlineVal := strings.Cut(arg, "=")
 
 	flg := f.lookupLong(arg, f.RelaxedParsing)
 
+	// negatable boolean flags may be given with a '--no-' prefix
+	if name, ok := strings.CutPrefix(arg, "no-"); flg == nil && ok {
+		if neg := f.lookupLong(name, f.RelaxedParsing); neg != nil && isNegatableFlag(neg) {
+			if inlineVal {
+				return nil, fmt.Errorf("flag '--%s' does not accept a value", arg)
+			}
+
+			if err := f.set(neg.Name, "false"); err != nil {
+				return nil, err
+			}
+
+			return arguments, nil
+		}
+	}
+
 	// similar to the stdlib, if we encounter a '--help' flag but none defined, return ErrHelp
 	if flg == nil && arg == "help" {
 		return nil, flag.ErrHelp
 	}
 
+	if flg == nil && f.UnknownFlagHandler != nil {
+		name := arg
+		if inlineVal {
+			name = arg + "=" + value
+		}
+
+		return arguments, f.UnknownFlagHandler(name)
+	}
+
+	if flg == nil && f.WarnUnknown {
+		_, _ = fmt.Fprintf(f.Output(), "warning: ignoring unknown flag '--%s'\n", arg)
+		return arguments, nil
+	}
+
+	if flg == nil && f.ContinueOnUnknown {
+		f.unknown = append(f.unknown, fmt.Errorf("flag '--%s' does not exist", arg))
+		return arguments, nil
+	}
+
 	if flg == nil {
 		return nil, fmt.Errorf("flag '--%s' does not exist", arg)
 	}
@@ -320,10 +549,14 @@ func (f *PosixFlagSet) parseLong(arg string, arguments []string) ([]string, erro
 		}
 	}
 
-	if err := f.Set(flg.Name, value); err != nil {
+	if err := f.set(flg.Name, value); err != nil {
 		return nil, err
 	}
 
+	if isGreedyFlag(flg) {
+		return f.consumeGreedy(flg, arguments)
+	}
+
 	return arguments, nil
 }
 
@@ -342,18 +575,38 @@ func (f *PosixFlagSet) parseShort(short string, arguments []string) ([]string, e
 		if flg == nil && args[0] == "h" {
 			return nil, flag.ErrHelp
 		}
+		if flg == nil && f.UnknownFlagHandler != nil {
+			if err := f.UnknownFlagHandler(args[0]); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+		if flg == nil && f.WarnUnknown {
+			_, _ = fmt.Fprintf(f.Output(), "warning: ignoring unknown flag '-%s'\n", args[0])
+			continue
+		}
+		if flg == nil && f.ContinueOnUnknown {
+			f.unknown = append(f.unknown, fmt.Errorf("flag '-%s' does not exist", args[0]))
+			continue
+		}
 		if flg == nil {
 			return nil, fmt.Errorf("flag '-%s' does not exist", args[0])
 		}
 
 		if isBoolFlag(flg) {
-			if err := f.Set(args[0], "true"); err != nil {
+			if err := f.set(args[0], "true"); err != nil {
 				return nil, err
 			}
 		} else {
 			if short != "" {
 				// rest is arg
-				if err := f.Set(args[0], short); err != nil {
+				if flags := f.trailingBoolFlags(short); f.StrictShortClusters && flags != "" {
+					return nil, fmt.Errorf("ambiguous value '%s' for flag '-%s': '%s' may be intended as flags, "+
+						"give the value as a separate argument instead", short, args[0], flags)
+				}
+
+				if err := f.set(args[0], short); err != nil {
 					return nil, err
 				}
 			} else {
@@ -362,13 +615,17 @@ func (f *PosixFlagSet) parseShort(short string, arguments []string) ([]string, e
 					return nil, fmt.Errorf("missing argument to flag '-%s'", args[0])
 				}
 
-				if err := f.Set(args[0], arguments[0]); err != nil {
+				if err := f.set(args[0], arguments[0]); err != nil {
 					return nil, err
 				}
 
 				arguments = arguments[1:]
 			}
 
+			if flg := f.Lookup(args[0]); isGreedyFlag(flg) {
+				return f.consumeGreedy(flg, arguments)
+			}
+
 			return arguments, nil
 		}
 	}
@@ -376,6 +633,46 @@ func (f *PosixFlagSet) parseShort(short string, arguments []string) ([]string, e
 	return arguments, nil
 }
 
+// trailingBoolFlags returns the longest suffix of value consisting only of the names of short boolean flags in f (e.g.
+// 'ab' for '12ab'), or an empty string if value doesn't end with the name of a short boolean flag.
+func (f *PosixFlagSet) trailingBoolFlags(value string) string {
+	runes := []rune(value)
+
+	i := len(runes)
+	for i > 0 {
+		if flg := f.Lookup(string(runes[i-1])); flg == nil || !isBoolFlag(flg) {
+			break
+		}
+
+		i--
+	}
+
+	return string(runes[i:])
+}
+
+// set updates the value of the flag with the given name, normalizing (and expanding) the value and emitting any
+// applicable deprecation warnings. Errors returned by the flag [flag.Value] are wrapped with the flag name.
+func (f *PosixFlagSet) set(name, value string) error {
+	value = f.normalize(name, value)
+
+	if f.ExpandEnv && isStringFlag(f.Lookup(name)) {
+		value = os.ExpandEnv(value)
+	}
+
+	f.warnDeprecated(name)
+	f.warnDeprecatedValue(name, value)
+
+	if err := f.FlagSet.Set(name, value); err != nil {
+		return fmt.Errorf("invalid value '%s' for flag '%s': %w", value, display(f.Lookup(name)), err)
+	}
+
+	if f.occurrences != nil {
+		f.occurrences[name]++
+	}
+
+	return nil
+}
+
 // lookupLong looks for a (long) flag with the given name in f. Returns nil if no flag found.
 //
 // When relaxed is true, partial flag name matches are permitted. If more than one flag name has the prefix name,
@@ -483,10 +780,99 @@ func (f *PosixFlagSet) defaultUsage() {
 	f.PrintDefaults()
 }
 
+// describe returns the lines of the description of flg rendered by [PosixFlagSet.PrintDefaults].
+func (f *PosixFlagSet) describe(flg *flag.Flag) []string {
+	description := unquote(flg)[1]
+
+	if long, ok := longUsage(flg); ok && f.PrintLongUsage {
+		description = strings.TrimSpace(long)
+	}
+
+	lines := strings.Split(description, "\n")
+	if f.UsageWidth <= 0 {
+		return lines
+	}
+
+	// usage lines are indented by six spaces
+	var wrapped []string
+	for _, line := range lines {
+		wrapped = append(wrapped, wrapWords(line, max(f.UsageWidth-6, 1))...)
+	}
+
+	return wrapped
+}
+
+// wrapWords splits text into lines of at most width characters, breaking lines between words. Words longer than width
+// are placed on a line of their own.
+func wrapWords(text string, width int) []string {
+	var (
+		lines   []string
+		current string
+	)
+
+	for _, word := range strings.Fields(text) {
+		if current != "" && len([]rune(current))+1+len([]rune(word)) > width {
+			lines = append(lines, current)
+			current = ""
+		}
+
+		if current != "" {
+			current += " "
+		}
+
+		current += word
+	}
+
+	return append(lines, current)
+}
+
+// displayDefault returns the default value of flg for display in usage text, truncated according to
+// [PosixFlagSet] MaxDefaultDisplayLen.
+func (f *PosixFlagSet) displayDefault(flg *flag.Flag) string {
+	value := []rune(flg.DefValue)
+	if f.MaxDefaultDisplayLen <= 0 || len(value) <= f.MaxDefaultDisplayLen {
+		return flg.DefValue
+	}
+
+	return string(value[:f.MaxDefaultDisplayLen-1]) + "…"
+}
+
 // unquote is a wrapper over the standard [flag.UnquoteUsage] which returns a slice, allowing it to be used as a
 // template func.
+//
+// If the usage of flg doesn't name the flag argument, the argument name is derived from the flag type for types in this
+// package: the allowed values of an [EnumVar], 'float' for a [Float32Var] or [Float64RangeVar], 'time' for a [TimeVar]
+// or [TimeLayoutVar], 'ip' for an [IPVar], 'cidr' for an [IPNetVar], 'size' for a [BytesVar] and 'file' for a
+// [FileVar]. The range of a [Float64RangeVar] is appended to the usage.
 func unquote(flg *flag.Flag) []string {
-	name, usage := flag.UnquoteUsage(flg)
+	// the argument name of standard flags is derived from the type of the innermost value (see [HiddenVar])
+	name, usage := flag.UnquoteUsage(&flag.Flag{Name: flg.Name, Usage: flg.Usage, Value: unwrap(flg.Value)})
+
+	if r, ok := unwrap(flg.Value).(*Float64RangeVar); ok {
+		usage = fmt.Sprintf("%s (range %s)", usage, r.Range())
+	}
+
+	if strings.Contains(flg.Usage, "`") {
+		return []string{name, usage}
+	}
+
+	switch v := unwrap(flg.Value).(type) {
+	case enumFlag:
+		name = strings.Join(v.Allowed(), "|")
+	case *Float32Var, *Float64RangeVar:
+		name = "float"
+	case *TimeVar, *TimeLayoutVar:
+		name = "time"
+	case *IPVar:
+		name = "ip"
+	case *IPNetVar:
+		name = "cidr"
+	case *BytesVar:
+		name = "size"
+	case *FileVar:
+		name = "file"
+	}
+
 	return []string{name, usage}
 }
 
@@ -501,7 +887,7 @@ func unquote(flg *flag.Flag) []string {
 func zero(flg *flag.Flag) (ok bool, err error) {
 	var z reflect.Value
 
-	if typ := reflect.TypeOf(flg.Value); typ.Kind() == reflect.Pointer {
+	if typ := reflect.TypeOf(unwrap(flg.Value)); typ.Kind() == reflect.Pointer {
 		z = reflect.New(typ.Elem())
 	} else {
 		z = reflect.Zero(typ)
@@ -518,8 +904,29 @@ func zero(flg *flag.Flag) (ok bool, err error) {
 	return
 }
 
+// wrapper is a [flag.Value] which wraps another [flag.Value] (e.g. [HiddenVar]).
+type wrapper interface {
+	flag.Value
+	Unwrap() flag.Value
+}
+
+// unwrap returns the innermost [flag.Value] wrapped by v (see [HiddenVar] and [LongUsageVar]). Returns v if it doesn't
+// wrap another value.
+func unwrap(v flag.Value) flag.Value {
+	for {
+		w, ok := v.(wrapper)
+		if !ok || w.Unwrap() == nil {
+			return v
+		}
+
+		v = w.Unwrap()
+	}
+}
+
 // areSame check if f1 and f2 have the same underlying [flag.Value].
 func areSame(f1, f2 flag.Value) bool {
+	f1, f2 = unwrap(f1), unwrap(f2)
+
 	var (
 		ref1 = reflect.ValueOf(f1)
 		ref2 = reflect.ValueOf(f2)
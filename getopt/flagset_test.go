@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"flag"
+	"os"
 	"slices"
 	"strings"
 	"testing"
@@ -163,6 +164,34 @@ func TestPosixFlagSet(t *testing.T) {
 			}
 		})
 
+		t.Run("should parse values resembling flags", func(t *testing.T) {
+			for args, expected := range map[string]string{
+				"--format=--json": "--json",
+				"--format=-x":     "-x",
+				"--format=--":     "--",
+				"--format=a=b":    "a=b",
+				"--format=":       "",
+				"--format --json": "--json",
+				"--format -- -x":  "--",
+				"-f--json":        "--json",
+				"-f -x":           "-x",
+			} {
+				var format string
+
+				fs := NewPosixFlagSet("test", flag.ContinueOnError)
+				fs.StringVar(&format, "format", "default", "output format")
+				Alias(fs.FlagSet, "format", "f")
+				fs.Bool("x", false, "extended")
+
+				if err := fs.Parse(strings.Fields(args)); err != nil {
+					t.Fatalf("unexpected error for '%s': %v", args, err)
+				}
+				if format != expected {
+					t.Fatalf("unexpected value for '%s': '%s'", args, format)
+				}
+			}
+		})
+
 		t.Run("should parse boolean long flags", func(t *testing.T) {
 			var (
 				b1 bool
@@ -298,6 +327,48 @@ func TestPosixFlagSet(t *testing.T) {
 			}
 		})
 
+		t.Run("should consume remainder of combined short flags as value", func(t *testing.T) {
+			var (
+				count uint
+				all   bool
+			)
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.UintVar(&count, "c", 0, "number of results")
+			fs.StringVar(new(string), "o", "", "output file")
+			fs.BoolVar(&all, "a", false, "show all")
+
+			err := fs.Parse([]string{"-c12", "-oa"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != 12 || all {
+				t.Fatalf("unexpected values: %d %v", count, all)
+			}
+		})
+
+		t.Run("should reject ambiguous combined short flags in strict mode", func(t *testing.T) {
+			var output string
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.Usage = func() {}
+			fs.StrictShortClusters = true
+			fs.UintVar(new(uint), "c", 0, "number of results")
+			fs.StringVar(&output, "o", "", "output file")
+			fs.Bool("a", false, "show all")
+			fs.Bool("b", false, "brief")
+
+			err := fs.Parse([]string{"-c12ab"})
+			if err == nil || !strings.Contains(err.Error(), "ambiguous value '12ab' for flag '-c': 'ab' may be intended "+
+				"as flags") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err := fs.Parse([]string{"-ofile.txt", "-ab", "-c", "12"}); err != nil || output != "file.txt" {
+				t.Fatalf("unexpected result: %q (%v)", output, err)
+			}
+		})
+
 		t.Run("should stop processing arguments after --", func(t *testing.T) {
 			var (
 				output string
@@ -578,6 +649,119 @@ func TestPosixFlagSet(t *testing.T) {
 			}
 		})
 
+		t.Run("should pass unknown flags to handler and continue", func(t *testing.T) {
+			var (
+				unknown []string
+				a, b    bool
+				count   uint
+			)
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.BoolVar(&a, "a", false, "a")
+			fs.BoolVar(&b, "b", false, "b")
+			fs.UintVar(&count, "count", 12, "number of results")
+			fs.UnknownFlagHandler = func(name string) error {
+				unknown = append(unknown, name)
+				return nil
+			}
+
+			err := fs.Parse([]string{"-aXb", "--all", "--since=1d", "--count", "3", "-Y", "arg"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !a || !b || count != 3 {
+				t.Fatalf("flags not updated with expected values: %v %v %d", a, b, count)
+			}
+			if !slices.Equal([]string{"X", "all", "since=1d", "Y"}, unknown) {
+				t.Fatalf("unexpected unknown flags: %v", unknown)
+			}
+			if !slices.Equal([]string{"arg"}, fs.Args()) {
+				t.Fatalf("unexpected remaining args: %v", fs.Args())
+			}
+		})
+
+		t.Run("should abort if unknown flag handler returns error", func(t *testing.T) {
+			var a bool
+
+			errUnknown := errors.New("unknown")
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.Usage = func() {}
+			fs.BoolVar(&a, "a", false, "a")
+			fs.UnknownFlagHandler = func(name string) error {
+				return errUnknown
+			}
+
+			if err := fs.Parse([]string{"-Xa"}); !errors.Is(err, errUnknown) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if a {
+				t.Fatalf("unexpected flag value: %v", a)
+			}
+		})
+
+		t.Run("should report all unknown flags when continuing on unknown flags", func(t *testing.T) {
+			var (
+				a     bool
+				count uint
+			)
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.Usage = func() {}
+			fs.ContinueOnUnknown = true
+			fs.BoolVar(&a, "a", false, "a")
+			fs.UintVar(&count, "count", 12, "number of results")
+
+			err := fs.Parse([]string{"--all", "-aXY", "--count", "3", "--since=1d", "arg"})
+			if err == nil {
+				t.Fatalf("expected error but was nil")
+			}
+
+			expected := "flag '--all' does not exist\nflag '-X' does not exist\nflag '-Y' does not exist\n" +
+				"flag '--since' does not exist"
+			if err.Error() != expected {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !a || count != 3 {
+				t.Fatalf("flags not updated with expected values: %v %d", a, count)
+			}
+			if !slices.Equal([]string{"arg"}, fs.Args()) {
+				t.Fatalf("unexpected remaining args: %v", fs.Args())
+			}
+		})
+
+		t.Run("should warn and continue when warning on unknown flags", func(t *testing.T) {
+			var (
+				buf   bytes.Buffer
+				a     bool
+				count uint
+			)
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&buf)
+			fs.WarnUnknown = true
+			fs.BoolVar(&a, "a", false, "a")
+			fs.UintVar(&count, "count", 12, "number of results")
+
+			err := fs.Parse([]string{"--all", "-Xa", "--since=1d", "--count", "3", "arg"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			expected := "warning: ignoring unknown flag '--all'\nwarning: ignoring unknown flag '-X'\n" +
+				"warning: ignoring unknown flag '--since'\n"
+			if buf.String() != expected {
+				t.Fatalf("unexpected warnings: '%s'", buf.String())
+			}
+			if !a || count != 3 {
+				t.Fatalf("flags not updated with expected values: %v %d", a, count)
+			}
+			if !slices.Equal([]string{"arg"}, fs.Args()) {
+				t.Fatalf("unexpected remaining args: %v", fs.Args())
+			}
+		})
+
 		t.Run("should return error if long flag arg missing", func(t *testing.T) {
 			var (
 				output string
@@ -711,6 +895,167 @@ func TestPosixFlagSet(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 		})
+
+		t.Run("should name the flag in errors returned by flag values", func(t *testing.T) {
+			errLevel := errors.New("level must be one of: debug, info")
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.Usage = func() {}
+			fs.Func("level", "log `level`", func(string) error { return errLevel })
+			Alias(fs.FlagSet, "level", "l")
+
+			// previously, the error of the flag value was returned as-is
+			err := fs.Parse([]string{"--level", "trace"})
+			if !errors.Is(err, errLevel) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err.Error() != "invalid value 'trace' for flag '--level': level must be one of: debug, info" {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = fs.Parse([]string{"-ltrace"})
+			if !errors.Is(err, errLevel) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err.Error() != "invalid value 'trace' for flag '-l': level must be one of: debug, info" {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+
+		t.Run("should parse single hyphen long flags if enabled", func(t *testing.T) {
+			var (
+				verbose bool
+				v       int
+				output  string
+			)
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.AllowSingleDashLong = true
+			fs.BoolVar(&verbose, "verbose", false, "verbose output")
+			fs.Var(Counter(&v), "v", "verbosity")
+			fs.StringVar(&output, "output", "-", "output `file`")
+
+			err := fs.Parse([]string{"-verbose", "-vv", "-output=out.txt", "arg"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !verbose || v != 2 || output != "out.txt" {
+				t.Fatalf("flags not updated with expected values: %v %d %s", verbose, v, output)
+			}
+			if !slices.Equal([]string{"arg"}, fs.Args()) {
+				t.Fatalf("unexpected remaining args: %v", fs.Args())
+			}
+		})
+
+		t.Run("should expand environment variables in string flags if enabled", func(t *testing.T) {
+			t.Setenv("CMDER_TEST_HOME", "/home/test")
+			t.Setenv("CMDER_TEST_UNSET", "")
+			os.Unsetenv("CMDER_TEST_UNSET")
+
+			var (
+				output string
+				hosts  StringsVar
+				count  int
+			)
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.ExpandEnv = true
+			fs.StringVar(&output, "output", "-", "output `file`")
+			fs.Var(&hosts, "host", "remote hosts")
+			fs.IntVar(&count, "count", 0, "count")
+
+			err := fs.Parse([]string{"--output", "$CMDER_TEST_HOME/out.txt", "--host=${CMDER_TEST_UNSET}local",
+				"--host", "remote"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output != "/home/test/out.txt" {
+				t.Fatalf("unexpected output: %s", output)
+			}
+			if !slices.Equal([]string{"local", "remote"}, hosts) {
+				t.Fatalf("unexpected hosts: %v", hosts)
+			}
+
+			t.Setenv("CMDER_TEST_COUNT", "3")
+			if err := fs.Parse([]string{"--count", "$CMDER_TEST_COUNT"}); err == nil {
+				t.Fatalf("expected error for non-string flag")
+			}
+		})
+
+		t.Run("should not expand environment variables unless enabled", func(t *testing.T) {
+			t.Setenv("CMDER_TEST_HOME", "/home/test")
+
+			var output string
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.StringVar(&output, "output", "-", "output `file`")
+
+			if err := fs.Parse([]string{"--output", "$CMDER_TEST_HOME/out.txt"}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if output != "$CMDER_TEST_HOME/out.txt" {
+				t.Fatalf("unexpected output: %s", output)
+			}
+		})
+
+		t.Run("should parse single hyphen long flags as short flags unless enabled", func(t *testing.T) {
+			var verbose bool
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.BoolVar(&verbose, "verbose", false, "verbose output")
+
+			err := fs.Parse([]string{"-verbose"})
+			if err == nil || !strings.Contains(err.Error(), "flag '-v' does not exist") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	})
+
+	t.Run("ConsumedArgs", func(t *testing.T) {
+		t.Run("should partition arguments into consumed and remaining", func(t *testing.T) {
+			var (
+				output string
+				all    bool
+			)
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.StringVar(&output, "output", "-", "output file")
+			fs.BoolVar(&all, "a", false, "all")
+
+			if fs.ConsumedArgs() != nil {
+				t.Fatalf("unexpected consumed args before parse: %v", fs.ConsumedArgs())
+			}
+
+			args := []string{"-a", "--output", "test.out", "--", "-a", "arg"}
+			if err := fs.Parse(args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal([]string{"-a", "--output", "test.out", "--"}, fs.ConsumedArgs()) {
+				t.Fatalf("unexpected consumed args: %v", fs.ConsumedArgs())
+			}
+			if !slices.Equal([]string{"-a", "arg"}, fs.Args()) {
+				t.Fatalf("unexpected remaining args: %v", fs.Args())
+			}
+			if !slices.Equal(args, append(slices.Clone(fs.ConsumedArgs()), fs.Args()...)) {
+				t.Fatalf("consumed and remaining args don't partition arguments")
+			}
+		})
+
+		t.Run("should return nil if parsing fails", func(t *testing.T) {
+			var output string
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.Usage = func() {}
+			fs.StringVar(&output, "output", "-", "output file")
+
+			if err := fs.Parse([]string{"--output"}); err == nil {
+				t.Fatalf("expected error but was nil")
+			}
+			if fs.ConsumedArgs() != nil {
+				t.Fatalf("unexpected consumed args: %v", fs.ConsumedArgs())
+			}
+		})
 	})
 
 	t.Run("Visit", func(t *testing.T) {
@@ -837,6 +1182,119 @@ func TestPosixFlagSet(t *testing.T) {
 			}
 		})
 
+		t.Run("should render aliases on a single line", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&buf)
+
+			fs.String("addr", "", "device `address`")
+			Alias(fs.FlagSet, "addr", "a")
+			fs.Bool("verbose", false, "verbose output")
+			Alias(fs.FlagSet, "verbose", "v")
+			Alias(fs.FlagSet, "verbose", "debug")
+
+			fs.PrintDefaults()
+
+			expected := `  -a <address>, --addr=<address>
+      device address
+
+  -v, --debug, --verbose
+      verbose output
+`
+			if buf.String() != expected {
+				t.Fatalf("unexpected usage string: '%s'", buf.String())
+			}
+		})
+
+		t.Run("should omit hidden flags only if all aliases are hidden", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&buf)
+
+			fs.String("output", "-", "output `file`")
+			Alias(fs.FlagSet, "output", "o")
+			fs.Uint("count", 12, "number of results")
+			Alias(fs.FlagSet, "count", "c")
+
+			Hide(fs.FlagSet, "o")
+			Hide(fs.FlagSet, "count")
+			Hide(fs.FlagSet, "c")
+
+			fs.PrintDefaults()
+
+			expected := "  --output=<file> (default -)\n      output file\n"
+			if buf.String() != expected {
+				t.Fatalf("unexpected usage string: '%s'", buf.String())
+			}
+		})
+
+		t.Run("should truncate long default values", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&buf)
+			fs.MaxDefaultDisplayLen = 12
+
+			fs.String("config", "/etc/example/config.json", "config `file`")
+			fs.String("name", "short", "name")
+
+			fs.PrintDefaults()
+
+			expected := "  --config=<file> (default /etc/exampl…)\n      config file\n\n" +
+				"  --name=<string> (default short)\n      name\n"
+			if buf.String() != expected {
+				t.Fatalf("unexpected usage string: '%s'", buf.String())
+			}
+			if def := fs.Lookup("config").DefValue; def != "/etc/example/config.json" {
+				t.Fatalf("unexpected default value: %s", def)
+			}
+		})
+
+		t.Run("should wrap long usage text", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&buf)
+			fs.UsageWidth = 40
+
+			fs.Bool("server-side", false, "If true, apply runs in the server instead of the client.\n\n"+
+				"See https://kubernetes.io/docs/reference/using-api/server-side-apply/ for details.")
+			fs.Bool("v", false, "verbose")
+
+			fs.PrintDefaults()
+
+			expected := "  --server-side\n" +
+				"      If true, apply runs in the server\n" +
+				"      instead of the client.\n" +
+				"      \n" +
+				"      See\n" +
+				"      https://kubernetes.io/docs/reference/using-api/server-side-apply/\n" +
+				"      for details.\n\n" +
+				"  -v\n" +
+				"      verbose\n"
+			if buf.String() != expected {
+				t.Fatalf("unexpected usage string: '%s'", buf.String())
+			}
+		})
+
+		t.Run("should not wrap usage text by default", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(&buf)
+
+			usage := strings.Repeat("very long usage ", 10)
+			fs.Bool("v", false, usage)
+
+			fs.PrintDefaults()
+
+			if expected := "  -v\n      " + usage + "\n"; buf.String() != expected {
+				t.Fatalf("unexpected usage string: '%s'", buf.String())
+			}
+		})
+
 		t.Run("should render in lexical order", func(t *testing.T) {
 			var buf bytes.Buffer
 
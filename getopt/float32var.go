@@ -0,0 +1,51 @@
+package getopt
+
+import (
+	"strconv"
+)
+
+// Float32Var is a [flag.Value] for flags that accept 32-bit floating point numbers. Float32Var also implements
+// [flag.Getter].
+//
+// Values are parsed with [strconv.ParseFloat] with a bit size of 32, avoiding lossy round-trips through float64.
+type Float32Var float32
+
+// Float32 returns a [Float32Var] for f.
+func Float32(f *float32) *Float32Var {
+	return (*Float32Var)(f)
+}
+
+// Float32Var defines a float32 flag with the specified name, default value and usage string. The argument p points to
+// a float32 variable in which to store the value of the flag.
+func (f *PosixFlagSet) Float32Var(p *float32, name string, value float32, usage string) {
+	*p = value
+	f.Var(Float32(p), name, usage)
+}
+
+// Float32 defines a float32 flag with the specified name, default value and usage string. The return value is the
+// address of a float32 variable that stores the value of the flag.
+func (f *PosixFlagSet) Float32(name string, value float32, usage string) *float32 {
+	p := new(float32)
+	f.Float32Var(p, name, value, usage)
+	return p
+}
+
+// String returns the shortest representation of the value that round-trips through [Float32Var.Set].
+func (v Float32Var) String() string {
+	return strconv.FormatFloat(float64(v), 'g', -1, 32)
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be parseable by [strconv.ParseFloat].
+func (v *Float32Var) Set(value string) error {
+	f, err := strconv.ParseFloat(value, 32)
+	if err == nil {
+		*v = Float32Var(f)
+	}
+
+	return err
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a float32.
+func (v *Float32Var) Get() any {
+	return float32(*v)
+}
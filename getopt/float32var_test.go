@@ -0,0 +1,63 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestFloat32Var(t *testing.T) {
+	t.Run("should parse float32 values", func(t *testing.T) {
+		var ratio float32
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Float32Var(&ratio, "ratio", 0.5, "compression ratio")
+		scale := fs.Float32("scale", 1, "scale factor")
+
+		if ratio != 0.5 || *scale != 1 {
+			t.Fatalf("vars not updated with expected default values: %v %v", ratio, *scale)
+		}
+
+		if err := fs.Parse([]string{"--ratio", "0.1", "--scale=1.5E1"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if ratio != float32(0.1) || *scale != 15 {
+			t.Fatalf("vars not updated with expected values: %v %v", ratio, *scale)
+		}
+		if v, _ := fs.GetValue("ratio"); v != float32(0.1) {
+			t.Fatalf("unexpected value: %v", v)
+		}
+		if s := fs.Lookup("ratio").Value.String(); s != "0.1" {
+			t.Fatalf("unexpected string value: %s", s)
+		}
+	})
+
+	t.Run("should reject values out of range", func(t *testing.T) {
+		var ratio float32
+
+		if err := Float32(&ratio).Set("1e39"); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("should render usage", func(t *testing.T) {
+		var (
+			buf          bytes.Buffer
+			ratio, scale float32
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.Float32Var(&ratio, "ratio", 0.5, "compression ratio")
+		fs.Float32Var(&scale, "scale", 0, "scale `factor`")
+
+		fs.PrintDefaults()
+
+		expected := "  --ratio=<float> (default 0.5)\n      compression ratio\n\n" +
+			"  --scale=<factor>\n      scale factor\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected usage string: '%s'", buf.String())
+		}
+	})
+}
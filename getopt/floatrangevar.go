@@ -0,0 +1,105 @@
+package getopt
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Float64RangeVar is a [flag.Value] for flags that accept 64-bit floating point numbers within a range. Float64RangeVar
+// also implements [flag.Getter].
+//
+// Values outside the range are rejected. Bounds are either both inclusive or both exclusive.
+// [PosixFlagSet.PrintDefaults] advertises the range in the flag description:
+//
+//	--sample-rate=<float> (default 0.1)
+//	    fraction of requests to trace (range [0, 1])
+//
+// To initialize a Float64RangeVar, see [Float64Range] or [PosixFlagSet.Float64RangeVar].
+type Float64RangeVar struct {
+	value     *float64
+	min, max  float64
+	inclusive bool
+}
+
+// Float64Range returns a [Float64RangeVar] for f, accepting values between min and max. If inclusive is true, min and
+// max are themselves accepted.
+func Float64Range(f *float64, min, max float64, inclusive bool) *Float64RangeVar {
+	return &Float64RangeVar{
+		value:     f,
+		min:       min,
+		max:       max,
+		inclusive: inclusive,
+	}
+}
+
+// Float64RangeVar defines a [Float64RangeVar] flag with the specified name, range, default value and usage string. The
+// argument p points to a float64 variable in which to store the value of the flag.
+func (f *PosixFlagSet) Float64RangeVar(p *float64, name string, min, max float64, inclusive bool, value float64,
+	usage string) {
+	*p = value
+	f.Var(Float64Range(p, min, max, inclusive), name, usage)
+}
+
+// Range returns the range of accepted values in interval notation (e.g. '[0, 1]' or '(0, 1)').
+func (r *Float64RangeVar) Range() string {
+	lower, upper := "(", ")"
+	if r.inclusive {
+		lower, upper = "[", "]"
+	}
+
+	return lower + formatFloat64(r.min) + ", " + formatFloat64(r.max) + upper
+}
+
+// String returns the value of the flag.
+func (r *Float64RangeVar) String() string {
+	if r == nil || r.value == nil {
+		return ""
+	}
+
+	return formatFloat64(*r.value)
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be parseable by [strconv.ParseFloat] and be within the
+// range.
+func (r *Float64RangeVar) Set(value string) error {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+
+	if !r.contains(f) {
+		return fmt.Errorf("getopt: value %s out of range %s", value, r.Range())
+	}
+
+	*r.value = f
+
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a float64.
+func (r *Float64RangeVar) Get() any {
+	return *r.value
+}
+
+// Validate fulfills the [Validator] interface, checking that the current value is within the range.
+func (r *Float64RangeVar) Validate() error {
+	if !r.contains(*r.value) {
+		return fmt.Errorf("getopt: value %s out of range %s", formatFloat64(*r.value), r.Range())
+	}
+
+	return nil
+}
+
+// contains checks if f is within the range.
+func (r *Float64RangeVar) contains(f float64) bool {
+	if r.inclusive {
+		return f >= r.min && f <= r.max
+	}
+
+	return f > r.min && f < r.max
+}
+
+// formatFloat64 formats f in its shortest representation.
+func formatFloat64(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,91 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestFloat64RangeVar(t *testing.T) {
+	t.Run("should accept values within range", func(t *testing.T) {
+		var rate float64
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Float64RangeVar(&rate, "sample-rate", 0, 1, true, 0.5, "sampling rate")
+
+		if rate != 0.5 {
+			t.Fatalf("rate var not updated with expected default value: %v", rate)
+		}
+
+		if err := fs.Parse([]string{"--sample-rate", "0.25"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rate != 0.25 {
+			t.Fatalf("rate var not updated with expected value: %v", rate)
+		}
+		if v, _ := fs.GetValue("sample-rate"); v != 0.25 {
+			t.Fatalf("unexpected value: %v", v)
+		}
+	})
+
+	t.Run("should respect inclusive and exclusive bounds", func(t *testing.T) {
+		testcases := []struct {
+			value     string
+			inclusive bool
+			ok        bool
+		}{
+			{value: "0", inclusive: true, ok: true},
+			{value: "1", inclusive: true, ok: true},
+			{value: "0", inclusive: false, ok: false},
+			{value: "1", inclusive: false, ok: false},
+			{value: "0.999", inclusive: false, ok: true},
+		}
+
+		for _, tc := range testcases {
+			var rate float64
+
+			err := Float64Range(&rate, 0, 1, tc.inclusive).Set(tc.value)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected error for %s (inclusive %v): %v", tc.value, tc.inclusive, err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatalf("expected error for %s (inclusive %v) but was nil", tc.value, tc.inclusive)
+			}
+		}
+	})
+
+	t.Run("should reject values out of range", func(t *testing.T) {
+		rate := 0.5
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.Float64RangeVar(&rate, "sample-rate", 0, 1, false, rate, "sampling rate")
+
+		err := fs.Parse([]string{"--sample-rate=1.5"})
+		expected := "invalid value '1.5' for flag '--sample-rate': getopt: value 1.5 out of range (0, 1)"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rate != 0.5 {
+			t.Fatalf("rate var unexpectedly updated: %v", rate)
+		}
+	})
+
+	t.Run("should render range in usage", func(t *testing.T) {
+		var (
+			buf  bytes.Buffer
+			rate float64
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.Float64RangeVar(&rate, "sample-rate", 0, 1, true, 0.1, "fraction of requests to trace")
+
+		fs.PrintDefaults()
+
+		expected := "  --sample-rate=<float> (default 0.1)\n      fraction of requests to trace (range [0, 1])\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected usage string: '%s'", buf.String())
+		}
+	})
+}
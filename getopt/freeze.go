@@ -0,0 +1,55 @@
+package getopt
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Freeze marks the flag set as read-only. Once frozen, the flag set may be read from many goroutines concurrently,
+// which is useful for servers parsing flags once at startup:
+//
+//	if err := fs.Parse(os.Args[1:]); err != nil {
+//		return err
+//	}
+//
+//	fs.Freeze()
+//
+// Methods which only read the flag set (e.g. [flag.FlagSet.Lookup], [PosixFlagSet.GetValue], [PosixFlagSet.Changed],
+// [PosixFlagSet.Args] and [PosixFlagSet.VisitAll]) are safe for concurrent use as long as the flag set isn't modified.
+// Calls to Freeze must happen before the concurrent reads begin.
+//
+// Modifying a frozen flag set with [PosixFlagSet.Parse], [PosixFlagSet.Set] or [PosixFlagSet.Var] (including the typed
+// variants of this package, like [PosixFlagSet.IPVar]) is a programming error: a warning is written to the flag set
+// output, since the modification may race with concurrent reads. Modifications made directly through the wrapped
+// [flag.FlagSet] or the [flag.Value] of a flag are not detected.
+func (f *PosixFlagSet) Freeze() {
+	f.frozen = true
+}
+
+// Frozen reports whether the flag set is read-only (see [PosixFlagSet.Freeze]).
+func (f *PosixFlagSet) Frozen() bool {
+	return f.frozen
+}
+
+// Set sets the value of the named flag, like [flag.FlagSet.Set]. Writes a warning to the flag set output if the flag
+// set is frozen (see [PosixFlagSet.Freeze]).
+func (f *PosixFlagSet) Set(name, value string) error {
+	f.warnFrozen("Set")
+
+	return f.FlagSet.Set(name, value)
+}
+
+// Var defines a flag with the specified name and usage string, like [flag.FlagSet.Var]. Writes a warning to the flag
+// set output if the flag set is frozen (see [PosixFlagSet.Freeze]).
+func (f *PosixFlagSet) Var(value flag.Value, name string, usage string) {
+	f.warnFrozen("Var")
+
+	f.FlagSet.Var(value, name, usage)
+}
+
+// warnFrozen writes a warning to the flag set output if the flag set is frozen, naming the method which modified it.
+func (f *PosixFlagSet) warnFrozen(method string) {
+	if f.frozen {
+		_, _ = fmt.Fprintf(f.Output(), "warning: %s called on frozen flag set '%s'\n", method, f.Name())
+	}
+}
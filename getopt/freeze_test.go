@@ -0,0 +1,96 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	t.Run("should permit concurrent reads after parse", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Int("count", 12, "count")
+		fs.String("name", "default", "name")
+		fs.Bool("verbose", false, "verbose")
+		Alias(fs.FlagSet, "count", "c")
+
+		if err := fs.Parse([]string{"-c", "3", "--verbose", "arg"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		fs.Freeze()
+
+		var wg sync.WaitGroup
+
+		for range 16 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for range 100 {
+					if v, ok := fs.GetValue("count"); !ok || v != 3 {
+						t.Errorf("unexpected value: %v", v)
+					}
+					if !fs.Changed("verbose") || fs.Changed("name") {
+						t.Errorf("unexpected changed flags")
+					}
+					if fs.Lookup("name").Value.String() != "default" {
+						t.Errorf("unexpected lookup result")
+					}
+					if fs.NArg() != 1 || fs.Arg(0) != "arg" {
+						t.Errorf("unexpected args: %v", fs.Args())
+					}
+
+					fs.VisitAll(func(*flag.Flag) {})
+				}
+			}()
+		}
+
+		wg.Wait()
+	})
+
+	t.Run("should warn if modified after freeze", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.Int("count", 12, "count")
+		fs.Freeze()
+
+		if err := fs.Set("count", "3"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := fs.Parse([]string{"--count", "4"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		fs.Var(Strings(new([]string)), "label", "labels")
+
+		expected := []string{
+			"warning: Set called on frozen flag set 'test'",
+			"warning: Parse called on frozen flag set 'test'",
+			"warning: Var called on frozen flag set 'test'",
+		}
+		if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); !slices.Equal(expected, lines) {
+			t.Fatalf("unexpected output:\n%s", buf.String())
+		}
+	})
+
+	t.Run("should not warn unless frozen", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.Int("count", 12, "count")
+
+		if err := fs.Parse([]string{"--count", "4"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fs.Frozen() || buf.Len() != 0 {
+			t.Fatalf("unexpected output:\n%s", buf.String())
+		}
+	})
+}
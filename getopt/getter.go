@@ -0,0 +1,108 @@
+package getopt
+
+import (
+	"flag"
+	"reflect"
+)
+
+// DefaultValue returns the typed default value of the flag with the given name. Returns false if the flag does not
+// exist or if its [flag.Value] does not implement [flag.Getter].
+//
+// The default value is obtained by parsing [flag.Flag] DefValue with a fresh copy of the flag [flag.Value], so the
+// result is unaffected by values set at the command line or directly through the [flag.Value]. Returns false if the
+// default value cannot be recovered.
+//
+//	fs.Duration("timeout", time.Minute, "request timeout")
+//	def, ok := fs.DefaultValue("timeout") // time.Minute, true
+func (f *PosixFlagSet) DefaultValue(name string) (any, bool) {
+	flg := f.Lookup(name)
+	if flg == nil {
+		return nil, false
+	}
+
+	if _, ok := getter(flg.Value); !ok {
+		return nil, false
+	}
+
+	return parseDefault(flg)
+}
+
+// GetValue returns the typed value of the flag with the given name. Returns false if the flag does not exist or if its
+// [flag.Value] does not implement [flag.Getter].
+//
+//	fs.Duration("timeout", time.Minute, "request timeout")
+//	_ = fs.Parse([]string{"--timeout", "5s"})
+//	v, ok := fs.GetValue("timeout") // 5*time.Second, true
+func (f *PosixFlagSet) GetValue(name string) (any, bool) {
+	flg := f.Lookup(name)
+	if flg == nil {
+		return nil, false
+	}
+
+	g, ok := getter(flg.Value)
+	if !ok {
+		return nil, false
+	}
+
+	return g.Get(), true
+}
+
+// parseDefault builds a fresh copy of the [flag.Value] of flg and sets it to the flag default value, returning the
+// typed value. Returns false if the copy cannot be constructed or does not accept the default value.
+func parseDefault(flg *flag.Flag) (value any, ok bool) {
+	v := unwrap(flg.Value)
+
+	defer func() {
+		if e := recover(); e != nil {
+			value, ok = nil, false
+		}
+	}()
+
+	var fresh reflect.Value
+
+	switch typ := reflect.TypeOf(v); typ.Kind() {
+	case reflect.Pointer:
+		fresh = reflect.New(typ.Elem())
+	case reflect.Map:
+		fresh = reflect.MakeMap(typ)
+	default:
+		return nil, false
+	}
+
+	g, isGetter := fresh.Interface().(flag.Getter)
+	if !isGetter {
+		return nil, false
+	}
+
+	// values which don't accept their zero value (e.g. empty slices) don't need to be set
+	if g.String() == flg.DefValue {
+		return g.Get(), true
+	}
+
+	if err := g.Set(flg.DefValue); err != nil {
+		return nil, false
+	}
+
+	return g.Get(), true
+}
+
+// isStringFlag checks if flg holds a string value, that is if its [flag.Getter] returns a string or []string.
+func isStringFlag(flg *flag.Flag) bool {
+	g, ok := getter(flg.Value)
+	if !ok {
+		return false
+	}
+
+	switch g.Get().(type) {
+	case string, []string:
+		return true
+	default:
+		return false
+	}
+}
+
+// getter returns the [flag.Getter] of v, unwrapping any wrapped flag values (e.g. [HiddenVar]).
+func getter(v flag.Value) (flag.Getter, bool) {
+	g, ok := unwrap(v).(flag.Getter)
+	return g, ok
+}
@@ -0,0 +1,165 @@
+package getopt
+
+import (
+	"flag"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDefaultValue(t *testing.T) {
+	var (
+		count   int
+		ratio   float64
+		name    string
+		all     bool
+		timeout time.Duration
+		hosts   = []string{"a", "b"}
+		labels  = map[string]string{"k": "v"}
+		ports   []int
+		since   time.Duration
+	)
+
+	fs := NewPosixFlagSet("test", flag.ContinueOnError)
+	fs.IntVar(&count, "count", 12, "count")
+	fs.Float64Var(&ratio, "ratio", 0.5, "ratio")
+	fs.StringVar(&name, "name", "default", "name")
+	fs.BoolVar(&all, "all", true, "all")
+	fs.DurationVar(&timeout, "timeout", time.Minute, "timeout")
+	fs.Var(Strings(&hosts), "hosts", "hosts")
+	fs.Var(Map(labels), "label", "labels")
+	fs.Var(Ints(&ports), "port", "ports")
+	fs.SecondsVar(&since, "since", 30*time.Second, "since")
+	Alias(fs.FlagSet, "count", "c")
+	Hide(fs.FlagSet, "since")
+
+	check := func(t *testing.T) {
+		if v, ok := fs.DefaultValue("count"); !ok || v.(int) != 12 {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.DefaultValue("c"); !ok || v.(int) != 12 {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.DefaultValue("ratio"); !ok || v.(float64) != 0.5 {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.DefaultValue("name"); !ok || v.(string) != "default" {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.DefaultValue("all"); !ok || v.(bool) != true {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.DefaultValue("timeout"); !ok || v.(time.Duration) != time.Minute {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.DefaultValue("hosts"); !ok || !slices.Equal(v.([]string), []string{"a", "b"}) {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.DefaultValue("label"); !ok || !maps.Equal(v.(map[string]string), map[string]string{"k": "v"}) {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.DefaultValue("port"); !ok || len(v.([]int)) != 0 {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.DefaultValue("since"); !ok || v.(time.Duration) != 30*time.Second {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+	}
+
+	t.Run("should return typed default values before parsing", check)
+
+	t.Run("should return typed default values after parsing", func(t *testing.T) {
+		args := []string{
+			"-c", "1", "--ratio", "1.5", "--name", "other", "--all=false", "--timeout", "1s", "--hosts", "c",
+			"--label", "x=y", "--port", "80", "--since", "5",
+		}
+
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		check(t)
+	})
+
+	t.Run("should return typed default values after setting values outside parse", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Int("n", 5, "n")
+
+		if err := fs.Lookup("n").Value.Set("42"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v, ok := fs.DefaultValue("n"); !ok || v.(int) != 5 {
+			t.Fatalf("unexpected default value: %v (%T)", v, v)
+		}
+		if v, ok := fs.GetValue("n"); !ok || v.(int) != 42 {
+			t.Fatalf("unexpected value: %v (%T)", v, v)
+		}
+	})
+
+	t.Run("should return false for unknown flags", func(t *testing.T) {
+		if v, ok := fs.DefaultValue("unknown"); ok || v != nil {
+			t.Fatalf("unexpected default value: %v", v)
+		}
+	})
+
+	t.Run("should return false for flags that are not getters", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Func("func", "func", func(string) error { return nil })
+
+		if v, ok := fs.DefaultValue("func"); ok || v != nil {
+			t.Fatalf("unexpected default value: %v", v)
+		}
+	})
+}
+
+func TestGetValue(t *testing.T) {
+	var (
+		count     int
+		verbosity uint8
+		hosts     []string
+		since     time.Time
+	)
+
+	fs := NewPosixFlagSet("test", flag.ContinueOnError)
+	fs.IntVar(&count, "count", 12, "count")
+	fs.Var(Counter(&verbosity), "v", "verbosity")
+	fs.Var(Strings(&hosts), "hosts", "hosts")
+	fs.Var(&HiddenVar{Time(&since)}, "since", "since")
+	fs.Func("func", "func", func(string) error { return nil })
+	Alias(fs.FlagSet, "count", "c")
+
+	if err := fs.Parse([]string{"-c", "3", "-vv", "--hosts", "a,b", "--since", "2025-01-01T00:00:00Z"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("should return typed values", func(t *testing.T) {
+		if v, ok := fs.GetValue("count"); !ok || v.(int) != 3 {
+			t.Fatalf("unexpected value: %v (%T)", v, v)
+		}
+		if v, ok := fs.GetValue("c"); !ok || v.(int) != 3 {
+			t.Fatalf("unexpected value: %v (%T)", v, v)
+		}
+		if v, ok := fs.GetValue("v"); !ok || v.(uint8) != 2 {
+			t.Fatalf("unexpected value: %v (%T)", v, v)
+		}
+		if v, ok := fs.GetValue("hosts"); !ok || !slices.Equal(v.([]string), []string{"a", "b"}) {
+			t.Fatalf("unexpected value: %v (%T)", v, v)
+		}
+		if v, ok := fs.GetValue("since"); !ok || !v.(time.Time).Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("unexpected value: %v (%T)", v, v)
+		}
+	})
+
+	t.Run("should return false for unknown flags", func(t *testing.T) {
+		if v, ok := fs.GetValue("unknown"); ok || v != nil {
+			t.Fatalf("unexpected value: %v", v)
+		}
+	})
+
+	t.Run("should return false for flags that are not getters", func(t *testing.T) {
+		if v, ok := fs.GetValue("func"); ok || v != nil {
+			t.Fatalf("unexpected value: %v", v)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package getopt
+
+import (
+	"flag"
+	"strings"
+	"unicode/utf8"
+)
+
+// GreedyStringsVar is a [flag.Value] for flags that consume all remaining values greedily. GreedyStringsVar also
+// implements [flag.Getter].
+//
+// Once a greedy flag is seen at the command line, its value and all subsequent arguments are collected into the slice
+// until the next recognized flag or the terminator "--". This is useful for flags accepting a command and its
+// arguments without quoting:
+//
+//	--exec cmd arg1 arg2 --verbose   // exec: [cmd arg1 arg2]
+//
+// Unlike [StringsVar], values are not split on commas. To register a greedy flag, see
+// [PosixFlagSet.GreedyStringsVar].
+type GreedyStringsVar []string
+
+// GreedyStrings returns a [GreedyStringsVar] for ss.
+func GreedyStrings(ss *[]string) *GreedyStringsVar {
+	return (*GreedyStringsVar)(ss)
+}
+
+// GreedyStringsVar defines a [GreedyStringsVar] flag with the specified name and usage string. The argument p points
+// to a []string variable in which to store the values of the flag.
+func (f *PosixFlagSet) GreedyStringsVar(p *[]string, name string, usage string) {
+	f.Var(GreedyStrings(p), name, usage)
+}
+
+// String returns the slice, formatted as space-separated values.
+func (s GreedyStringsVar) String() string {
+	return strings.Join(s, " ")
+}
+
+// Set fulfills the [flag.Value] interface. The value is appended to the slice.
+func (s *GreedyStringsVar) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a
+// []string.
+func (s GreedyStringsVar) Get() any {
+	return []string(s)
+}
+
+// IsGreedy marks the flag as consuming all remaining values.
+func (s *GreedyStringsVar) IsGreedy() bool {
+	return true
+}
+
+// greedyFlag is a [flag.Value] that also implements a method IsGreedy, used to determine if the flag consumes all
+// remaining values.
+type greedyFlag interface {
+	flag.Value
+	IsGreedy() bool
+}
+
+// isGreedyFlag checks if the given flag has a [flag.Value] which is a greedy flag.
+func isGreedyFlag(flg *flag.Flag) bool {
+	if flg == nil {
+		return false
+	}
+
+	gf, ok := unwrap(flg.Value).(greedyFlag)
+	return ok && gf.IsGreedy()
+}
+
+// consumeGreedy sets the greedy flag flg with every argument until the next recognized flag or terminator, returning
+// the remaining arguments.
+func (f *PosixFlagSet) consumeGreedy(flg *flag.Flag, arguments []string) ([]string, error) {
+	for len(arguments) > 0 && arguments[0] != "--" && !f.isFlag(arguments[0]) {
+		if err := f.set(flg.Name, arguments[0]); err != nil {
+			return nil, err
+		}
+
+		arguments = arguments[1:]
+	}
+
+	return arguments, nil
+}
+
+// isFlag checks if arg refers to a flag recognized by f.
+func (f *PosixFlagSet) isFlag(arg string) bool {
+	if long, ok := strings.CutPrefix(arg, "--"); ok {
+		long, _, _ = strings.Cut(long, "=")
+
+		if f.lookupLong(long, f.RelaxedParsing) != nil {
+			return true
+		}
+
+		name, ok := strings.CutPrefix(long, "no-")
+		if flg := f.lookupLong(name, f.RelaxedParsing); ok && flg != nil && isNegatableFlag(flg) {
+			return true
+		}
+
+		return false
+	}
+
+	if short, ok := strings.CutPrefix(arg, "-"); ok && short != "" {
+		r, _ := utf8.DecodeRuneInString(short)
+		return f.Lookup(string(r)) != nil
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+package getopt
+
+import (
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestGreedyStringsVar(t *testing.T) {
+	testcases := []struct {
+		name    string
+		args    []string
+		exec    []string
+		verbose bool
+		rest    []string
+	}{
+		{
+			name: "should collect all remaining values",
+			args: []string{"--exec", "cmd", "arg1", "-", "arg2"},
+			exec: []string{"cmd", "arg1", "-", "arg2"},
+		}, {
+			name: "should accept inline value",
+			args: []string{"--exec=cmd", "arg1"},
+			exec: []string{"cmd", "arg1"},
+		}, {
+			name:    "should stop at known long flag",
+			args:    []string{"--exec", "cmd", "arg1", "--verbose", "rest"},
+			exec:    []string{"cmd", "arg1"},
+			verbose: true,
+			rest:    []string{"rest"},
+		}, {
+			name:    "should stop at known short flag",
+			args:    []string{"-x", "cmd", "arg1", "-v", "rest"},
+			exec:    []string{"cmd", "arg1"},
+			verbose: true,
+			rest:    []string{"rest"},
+		}, {
+			name: "should stop at terminator",
+			args: []string{"-xcmd", "arg1", "--", "-v"},
+			exec: []string{"cmd", "arg1"},
+			rest: []string{"-v"},
+		}, {
+			name: "should collect unknown flags",
+			args: []string{"--exec", "ls", "-l", "--all"},
+			exec: []string{"ls", "-l", "--all"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				exec    []string
+				verbose bool
+			)
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.GreedyStringsVar(&exec, "exec", "execute `command`")
+			fs.BoolVar(&verbose, "verbose", false, "verbose output")
+			Alias(fs.FlagSet, "exec", "x")
+			Alias(fs.FlagSet, "verbose", "v")
+
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(tc.exec, exec) {
+				t.Fatalf("unexpected exec value: %v", exec)
+			}
+			if tc.verbose != verbose {
+				t.Fatalf("unexpected verbose value: %v", verbose)
+			}
+			if !slices.Equal(tc.rest, fs.Args()) {
+				t.Fatalf("unexpected remaining args: %v", fs.Args())
+			}
+		})
+	}
+}
@@ -49,8 +49,37 @@ func (h *HiddenVar) String() string {
 	return h.Value.String()
 }
 
-// isHiddenFlag checks if the given flag has a [flag.Value] which indicates that flg is hidden.
+// Unwrap returns the parent [flag.Value].
+func (h *HiddenVar) Unwrap() flag.Value {
+	if h == nil {
+		return nil
+	}
+
+	return h.Value
+}
+
+// IsHidden checks if flg is hidden (see [Hide]), which is the case if the flag [flag.Value] is (or wraps) a [HiddenFlag]
+// reporting itself as hidden. Usage renderers other than [PosixFlagSet.PrintDefaults] can use this to omit hidden
+// flags.
+func IsHidden(flg *flag.Flag) bool {
+	return isHiddenFlag(flg)
+}
+
+// isHiddenFlag checks if the given flag has a [flag.Value] (or wraps a [flag.Value]) which indicates that flg is
+// hidden.
 func isHiddenFlag(flg *flag.Flag) bool {
-	hf, ok := flg.Value.(HiddenFlag)
-	return ok && hf.IsHiddenFlag()
+	for v := flg.Value; v != nil; {
+		if hf, ok := v.(HiddenFlag); ok && hf.IsHiddenFlag() {
+			return true
+		}
+
+		w, ok := v.(wrapper)
+		if !ok {
+			return false
+		}
+
+		v = w.Unwrap()
+	}
+
+	return false
 }
@@ -0,0 +1,106 @@
+package getopt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IntsVar is a [flag.Value] for flags that accept one or more integer values. IntsVar also implements [flag.Getter].
+//
+// IntsVar collects integer arguments into a slice. Multiple values may be comma separated (e.g. 80,443). Like the
+// standard integer flags, binary/octal/decimal/hexadecimal numbers are accepted (see [strconv.ParseInt]).
+//
+//	80
+//	80,443,8080
+//	0x50,0o673
+type IntsVar []int
+
+// Ints returns an [IntsVar] for is.
+func Ints(is *[]int) *IntsVar {
+	return (*IntsVar)(is)
+}
+
+// String returns the slice, formatted as comma-separated values.
+func (s IntsVar) String() string {
+	return formatInts(s)
+}
+
+// Set fulfills the [flag.Value] interface.
+func (s *IntsVar) Set(value string) error {
+	values, err := parseInts(value, strconv.IntSize)
+	if err != nil {
+		return err
+	}
+
+	for _, val := range values {
+		*s = append(*s, int(val))
+	}
+
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a []int.
+func (s IntsVar) Get() any {
+	return []int(s)
+}
+
+// Int64sVar is a [flag.Value] for flags that accept one or more 64-bit integer values. Int64sVar also implements
+// [flag.Getter].
+//
+// Int64sVar behaves like [IntsVar], but collects values into a slice of int64.
+type Int64sVar []int64
+
+// Int64s returns an [Int64sVar] for is.
+func Int64s(is *[]int64) *Int64sVar {
+	return (*Int64sVar)(is)
+}
+
+// String returns the slice, formatted as comma-separated values.
+func (s Int64sVar) String() string {
+	return formatInts(s)
+}
+
+// Set fulfills the [flag.Value] interface.
+func (s *Int64sVar) Set(value string) error {
+	values, err := parseInts(value, 64)
+	if err != nil {
+		return err
+	}
+
+	*s = append(*s, values...)
+
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a []int64.
+func (s Int64sVar) Get() any {
+	return []int64(s)
+}
+
+// parseInts parses a set of comma-separated integers that fit into bitSize.
+func parseInts(value string, bitSize int) ([]int64, error) {
+	var values []int64
+
+	for token := range strings.SplitSeq(value, ",") {
+		val, err := strconv.ParseInt(strings.TrimSpace(token), 0, bitSize)
+		if err != nil {
+			return nil, fmt.Errorf("getopt: malformed integer '%s' in value: %s", token, value)
+		}
+
+		values = append(values, val)
+	}
+
+	return values, nil
+}
+
+// formatInts formats a slice of integers as comma-separated values.
+func formatInts[T int | int64](values []T) string {
+	var entries []string
+
+	for _, val := range values {
+		entries = append(entries, strconv.FormatInt(int64(val), 10))
+	}
+
+	return strings.Join(entries, ",")
+}
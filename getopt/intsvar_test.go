@@ -0,0 +1,85 @@
+package getopt
+
+import (
+	"flag"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIntsVar(t *testing.T) {
+	t.Run("should parse comma-separated and repeated values", func(t *testing.T) {
+		var ports []int
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Var(Ints(&ports), "port", "listen ports")
+
+		err := fs.Parse([]string{"--port", "80,443", "--port=0x1F90", "--port", "0b1,0o7"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !slices.Equal([]int{80, 443, 8080, 1, 7}, ports) {
+			t.Fatalf("unexpected result: %v", ports)
+		}
+	})
+
+	t.Run("should return error naming malformed entry", func(t *testing.T) {
+		var ports []int
+
+		err := Ints(&ports).Set("80,http,443")
+		if err == nil || !strings.Contains(err.Error(), "'http'") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should round-trip String through Set", func(t *testing.T) {
+		var (
+			ports  = []int{-1, 80, 443}
+			result []int
+		)
+
+		if err := Ints(&result).Set(Ints(&ports).String()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(ports, result) {
+			t.Fatalf("unexpected result: %v", result)
+		}
+	})
+
+	t.Run("should not panic if calling String on nil value", func(t *testing.T) {
+		var z IntsVar
+
+		if result := z.String(); result != "" {
+			t.Fatalf("unexpected result: %s", result)
+		}
+	})
+}
+
+func TestInt64sVar(t *testing.T) {
+	t.Run("should parse comma-separated and repeated values", func(t *testing.T) {
+		var sizes []int64
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Var(Int64s(&sizes), "size", "sizes")
+		Alias(fs.FlagSet, "size", "s")
+
+		err := fs.Parse([]string{"-s", "9223372036854775807,-12", "--size=0x10"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !slices.Equal([]int64{9223372036854775807, -12, 16}, sizes) {
+			t.Fatalf("unexpected result: %v", sizes)
+		}
+	})
+
+	t.Run("should return error if value out of range", func(t *testing.T) {
+		var sizes []int64
+
+		err := Int64s(&sizes).Set("9223372036854775808")
+		if err == nil || !strings.Contains(err.Error(), "'9223372036854775808'") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
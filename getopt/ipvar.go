@@ -0,0 +1,92 @@
+package getopt
+
+import (
+	"fmt"
+	"net"
+)
+
+// IPVar is a [flag.Value] for flags that accept IPv4 or IPv6 addresses (e.g. '0.0.0.0' or '::1'), parsed with
+// [net.ParseIP]. IPVar also implements [flag.Getter].
+type IPVar net.IP
+
+// IP returns an [IPVar] for ip.
+func IP(ip *net.IP) *IPVar {
+	return (*IPVar)(ip)
+}
+
+// IPVar defines an [IPVar] flag with the specified name, default value and usage string. The argument p points to a
+// [net.IP] variable in which to store the value of the flag.
+func (f *PosixFlagSet) IPVar(p *net.IP, name string, value net.IP, usage string) {
+	*p = value
+	f.Var(IP(p), name, usage)
+}
+
+// String returns the address, formatted by [net.IP.String], or an empty string if no address is set.
+func (i IPVar) String() string {
+	if len(i) == 0 {
+		return ""
+	}
+
+	return net.IP(i).String()
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be an IPv4 or IPv6 address.
+func (i *IPVar) Set(value string) error {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return fmt.Errorf("invalid IP address '%s'", value)
+	}
+
+	*i = IPVar(ip)
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a [net.IP].
+func (i *IPVar) Get() any {
+	return net.IP(*i)
+}
+
+// IPNetVar is a [flag.Value] for flags that accept networks in CIDR notation (e.g. '10.0.0.0/8'), parsed with
+// [net.ParseCIDR]. IPNetVar also implements [flag.Getter].
+//
+// The flag holds the network denoted by the value, so host bits are cleared ('10.1.2.3/8' is stored as '10.0.0.0/8').
+type IPNetVar net.IPNet
+
+// IPNet returns an [IPNetVar] for n.
+func IPNet(n *net.IPNet) *IPNetVar {
+	return (*IPNetVar)(n)
+}
+
+// IPNetVar defines an [IPNetVar] flag with the specified name, default value and usage string. The argument p points
+// to a [net.IPNet] variable in which to store the value of the flag.
+func (f *PosixFlagSet) IPNetVar(p *net.IPNet, name string, value net.IPNet, usage string) {
+	*p = value
+	f.Var(IPNet(p), name, usage)
+}
+
+// String returns the network in CIDR notation, formatted by [net.IPNet.String], or an empty string if no network is
+// set.
+func (n IPNetVar) String() string {
+	if len(n.IP) == 0 {
+		return ""
+	}
+
+	return (*net.IPNet)(&n).String()
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be a network in CIDR notation.
+func (n *IPNetVar) Set(value string) error {
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR address '%s'", value)
+	}
+
+	*n = IPNetVar(*network)
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a
+// [net.IPNet].
+func (n *IPNetVar) Get() any {
+	return net.IPNet(*n)
+}
@@ -0,0 +1,122 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestIPVar(t *testing.T) {
+	t.Run("should parse addresses", func(t *testing.T) {
+		var bind net.IP
+
+		for _, arg := range []string{"0.0.0.0", "192.168.1.10", "::1", "fe80::1"} {
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.IPVar(&bind, "bind", nil, "bind address")
+
+			if err := fs.Parse([]string{"--bind", arg}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bind.Equal(net.ParseIP(arg)) {
+				t.Fatalf("unexpected result for '%s': %v", arg, bind)
+			}
+			if v, ok := fs.GetValue("bind"); !ok || !v.(net.IP).Equal(bind) {
+				t.Fatalf("unexpected value: %v", v)
+			}
+		}
+	})
+
+	t.Run("should return error naming flag and value", func(t *testing.T) {
+		var bind net.IP
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.IPVar(&bind, "bind", nil, "bind address")
+
+		err := fs.Parse([]string{"--bind", "300.0.0.1"})
+		if err == nil || !strings.Contains(err.Error(), "'--bind'") ||
+			!strings.Contains(err.Error(), "invalid IP address '300.0.0.1'") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should render usage", func(t *testing.T) {
+		var (
+			buf        bytes.Buffer
+			bind, peer net.IP
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.IPVar(&bind, "bind", net.IPv4zero, "bind address")
+		fs.IPVar(&peer, "peer", nil, "peer address")
+
+		fs.PrintDefaults()
+
+		expected := "  --bind=<ip> (default 0.0.0.0)\n      bind address\n\n  --peer=<ip>\n      peer address\n"
+		if buf.String() != expected {
+			t.Fatalf("unexpected usage: %q", buf.String())
+		}
+	})
+}
+
+func TestIPNetVar(t *testing.T) {
+	t.Run("should parse networks", func(t *testing.T) {
+		var cidr net.IPNet
+
+		testcases := map[string]string{
+			"10.0.0.0/8":    "10.0.0.0/8",
+			"10.1.2.3/8":    "10.0.0.0/8",
+			"2001:db8::/32": "2001:db8::/32",
+		}
+
+		for arg, expected := range testcases {
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.IPNetVar(&cidr, "cidr", net.IPNet{}, "allowed network")
+
+			if err := fs.Parse([]string{"--cidr", arg}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cidr.String() != expected {
+				t.Fatalf("unexpected result for '%s': %v", arg, cidr.String())
+			}
+			v, ok := fs.GetValue("cidr")
+			if network := v.(net.IPNet); !ok || network.String() != expected {
+				t.Fatalf("unexpected value: %v", v)
+			}
+		}
+	})
+
+	t.Run("should return error naming flag and value", func(t *testing.T) {
+		var cidr net.IPNet
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.IPNetVar(&cidr, "cidr", net.IPNet{}, "allowed network")
+
+		err := fs.Parse([]string{"--cidr", "10.0.0.0"})
+		if err == nil || !strings.Contains(err.Error(), "'--cidr'") ||
+			!strings.Contains(err.Error(), "invalid CIDR address '10.0.0.0'") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should render usage", func(t *testing.T) {
+		var (
+			buf  bytes.Buffer
+			cidr net.IPNet
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.IPNetVar(&cidr, "cidr", net.IPNet{}, "allowed network")
+
+		fs.PrintDefaults()
+
+		if expected := "  --cidr=<cidr>\n      allowed network\n"; buf.String() != expected {
+			t.Fatalf("unexpected usage: %q", buf.String())
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package getopt
+
+import (
+	"flag"
+)
+
+// LongUsageVar is a [flag.Value] which carries a long description of the flag in addition to the (short) flag usage
+// string. The long description is rendered by [PosixFlagSet.PrintDefaults] when [PosixFlagSet] PrintLongUsage is
+// set, while the short usage string is rendered otherwise.
+//
+// The long description may span multiple lines.
+//
+// To register a flag with a long description, see [PosixFlagSet.VarLong].
+type LongUsageVar struct {
+	flag.Value
+
+	// The long description of the flag.
+	Long string
+}
+
+// VarLong defines a flag with the specified name, short usage string and long usage string. The flag value is wrapped
+// in a [LongUsageVar].
+//
+// Aliases of the flag (see [Alias]) should be registered after calling VarLong.
+func (f *PosixFlagSet) VarLong(value flag.Value, name, shortUsage, longUsage string) {
+	f.Var(&LongUsageVar{Value: value, Long: longUsage}, name, shortUsage)
+}
+
+// String returns the parent [flag.Value].
+func (l *LongUsageVar) String() string {
+	if l == nil || l.Value == nil {
+		return ""
+	}
+
+	return l.Value.String()
+}
+
+// LongUsage returns the long description of the flag.
+func (l *LongUsageVar) LongUsage() string {
+	return l.Long
+}
+
+// IsBoolFlag returns true if the parent [flag.Value] is a boolean flag.
+func (l *LongUsageVar) IsBoolFlag() bool {
+	bf, ok := unwrap(l.Value).(boolFlag)
+	return ok && bf.IsBoolFlag()
+}
+
+// Unwrap returns the parent [flag.Value].
+func (l *LongUsageVar) Unwrap() flag.Value {
+	if l == nil {
+		return nil
+	}
+
+	return l.Value
+}
+
+// longUsage returns the long description of flg, if it has one.
+func longUsage(flg *flag.Flag) (string, bool) {
+	for v := flg.Value; v != nil; {
+		if lv, ok := v.(*LongUsageVar); ok && lv != nil {
+			return lv.Long, true
+		}
+
+		w, ok := v.(wrapper)
+		if !ok {
+			return "", false
+		}
+
+		v = w.Unwrap()
+	}
+
+	return "", false
+}
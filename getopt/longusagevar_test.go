@@ -0,0 +1,77 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestLongUsageVar(t *testing.T) {
+	setup := func(buf *bytes.Buffer) *PosixFlagSet {
+		var (
+			cascade string
+			force   bool
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(buf)
+		fs.VarLong(Enum(&cascade, "background", "orphan"), "cascade", "deletion cascading strategy",
+			"Must be \"background\" or \"orphan\". Defaults to background.\nOrphaned dependents are left as-is.")
+		fs.VarLong((*NegatableBoolVar)(&force), "force", "skip confirmation", "Delete without asking for confirmation.")
+		Alias(fs.FlagSet, "force", "f")
+
+		return fs
+	}
+
+	t.Run("should render short usage by default", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		setup(&buf).PrintDefaults()
+
+		expected := `  --cascade=<background|orphan>
+      deletion cascading strategy
+
+  -f, --force, --no-force
+      skip confirmation
+`
+		if buf.String() != expected {
+			t.Fatalf("unexpected usage string: '%s'", buf.String())
+		}
+	})
+
+	t.Run("should render long usage if enabled", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		fs := setup(&buf)
+		fs.PrintLongUsage = true
+		fs.PrintDefaults()
+
+		expected := `  --cascade=<background|orphan>
+      Must be "background" or "orphan". Defaults to background.
+      Orphaned dependents are left as-is.
+
+  -f, --force, --no-force
+      Delete without asking for confirmation.
+`
+		if buf.String() != expected {
+			t.Fatalf("unexpected usage string: '%s'", buf.String())
+		}
+	})
+
+	t.Run("should preserve wrapped value behavior", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		fs := setup(&buf)
+		fs.Usage = func() {}
+
+		if err := fs.Parse([]string{"-f", "--cascade=orphan", "--no-force"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v, _ := fs.GetValue("cascade"); v != "orphan" {
+			t.Fatalf("unexpected value: %v", v)
+		}
+		if v, _ := fs.GetValue("force"); v != false {
+			t.Fatalf("unexpected value: %v", v)
+		}
+	})
+}
@@ -33,44 +33,140 @@ func (m MapVar) String() string {
 		entries = append(entries, k+"="+m[k])
 	}
 
-	var builder strings.Builder
+	return formatMapEntries(entries)
+}
 
-	w := csv.NewWriter(&builder)
-	if err := w.Write(entries); err != nil {
-		panic(err)
+// Set fulfills the [flag.Value] interface. The given value must be a set of key-value pairs.
+func (m MapVar) Set(value string) error {
+	entries, err := parseMapEntries(value)
+	if err != nil {
+		return err
 	}
 
-	w.Flush()
+	for _, entry := range entries {
+		k, v, _ := strings.Cut(entry, "=")
+		m[k] = v
+	}
 
-	if err := w.Error(); err != nil {
-		panic(err)
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a
+// map[string]string.
+func (m MapVar) Get() any {
+	return map[string]string(m)
+}
+
+// OrderedMapVar is like [MapVar], but remembers the order in which keys were first set. OrderedMapVar also implements
+// [flag.Getter]. This is useful when the order of the entries given by the user matters (e.g. when rendering
+// variables).
+//
+// The zero value is an empty map ready to use.
+//
+//	var vars getopt.OrderedMapVar
+//	fs.Var(&vars, "var", "template `key=value`")
+//
+//	--var b=2 --var a=1,c=3   ->   vars.Keys() == [b a c]
+//
+// Setting a key again updates its value but not its position.
+type OrderedMapVar struct {
+	keys   []string
+	values map[string]string
+}
+
+// Keys returns the keys of the map in insertion order.
+func (m *OrderedMapVar) Keys() []string {
+	if m == nil {
+		return nil
 	}
 
-	return strings.TrimSuffix(builder.String(), "\n")
+	return slices.Clone(m.keys)
+}
+
+// Lookup returns the value of the given key and whether it is present in the map.
+func (m *OrderedMapVar) Lookup(key string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// String returns the map, formatted as a set of key-value pairs in insertion order.
+func (m *OrderedMapVar) String() string {
+	if m == nil {
+		return ""
+	}
+
+	var entries []string
+
+	for _, k := range m.keys {
+		entries = append(entries, k+"="+m.values[k])
+	}
+
+	return formatMapEntries(entries)
 }
 
 // Set fulfills the [flag.Value] interface. The given value must be a set of key-value pairs.
-func (m MapVar) Set(value string) error {
+func (m *OrderedMapVar) Set(value string) error {
+	entries, err := parseMapEntries(value)
+	if err != nil {
+		return err
+	}
+
+	if m.values == nil {
+		m.values = map[string]string{}
+	}
+
+	for _, entry := range entries {
+		k, v, _ := strings.Cut(entry, "=")
+
+		if _, ok := m.values[k]; !ok {
+			m.keys = append(m.keys, k)
+		}
+
+		m.values[k] = v
+	}
+
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a
+// map[string]string. Use [OrderedMapVar.Keys] to iterate the map in insertion order.
+func (m *OrderedMapVar) Get() any {
+	return maps.Clone(m.values)
+}
+
+// parseMapEntries parses a set of comma-separated key-value pairs, returning the unparsed entries (e.g. 'key=value').
+func parseMapEntries(value string) ([]string, error) {
 	r := csv.NewReader(strings.NewReader(value))
 
 	pairs, err := r.ReadAll()
 	if err != nil {
-		return fmt.Errorf("getopt: malformed map value: %s", value)
+		return nil, fmt.Errorf("getopt: malformed map value: %s", value)
 	}
 	if len(pairs) != 1 {
-		return fmt.Errorf("getopt: malformed map value: %s", value)
+		return nil, fmt.Errorf("getopt: malformed map value: %s", value)
 	}
 
-	for _, pair := range pairs[0] {
-		k, v, _ := strings.Cut(pair, "=")
-		m[k] = v
+	return pairs[0], nil
+}
+
+// formatMapEntries formats key-value pair entries (e.g. 'key=value') as comma-separated values.
+func formatMapEntries(entries []string) string {
+	var builder strings.Builder
+
+	w := csv.NewWriter(&builder)
+	if err := w.Write(entries); err != nil {
+		panic(err)
 	}
 
-	return nil
-}
+	w.Flush()
 
-// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a
-// map[string]string.
-func (m MapVar) Get() any {
-	return map[string]string(m)
+	if err := w.Error(); err != nil {
+		panic(err)
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n")
 }
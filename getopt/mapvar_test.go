@@ -3,6 +3,7 @@ package getopt
 import (
 	"flag"
 	"maps"
+	"slices"
 	"testing"
 )
 
@@ -135,3 +136,45 @@ func TestMapVar(t *testing.T) {
 		}
 	})
 }
+
+func TestOrderedMapVar(t *testing.T) {
+	t.Run("should preserve insertion order", func(t *testing.T) {
+		var vars OrderedMapVar
+
+		fs := flag.NewFlagSet("map", flag.ContinueOnError)
+		fs.Var(&vars, "var", "test")
+
+		if err := fs.Parse([]string{"-var", "b=2", "-var", `a=1,"c=x, y",b=4`}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if keys := vars.Keys(); !slices.Equal([]string{"b", "a", "c"}, keys) {
+			t.Fatalf("unexpected keys: %v", keys)
+		}
+		if v, ok := vars.Lookup("b"); !ok || v != "4" {
+			t.Fatalf("unexpected value: %s", v)
+		}
+		if s := vars.String(); s != `b=4,a=1,"c=x, y"` {
+			t.Fatalf("unexpected string value: %s", s)
+		}
+		if m := vars.Get().(map[string]string); !maps.Equal(map[string]string{"a": "1", "b": "4", "c": "x, y"}, m) {
+			t.Fatalf("unexpected map value: %v", m)
+		}
+	})
+
+	t.Run("should error for malformed flags", func(t *testing.T) {
+		var vars OrderedMapVar
+
+		if err := vars.Set(`HELLO="WORLD`); err == nil {
+			t.Fatalf("expected error but was nil")
+		}
+	})
+
+	t.Run("should not panic if calling String on nil value", func(t *testing.T) {
+		var z *OrderedMapVar
+
+		if result := z.String(); result != "" {
+			t.Fatalf("unexpected result: %s", result)
+		}
+	})
+}
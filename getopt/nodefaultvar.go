@@ -0,0 +1,83 @@
+package getopt
+
+import (
+	"flag"
+	"fmt"
+)
+
+// NoDefaultVar is a [flag.Value] whose default value is omitted from [PosixFlagSet.PrintDefaults] output. This is
+// useful for flags with noisy or sensitive defaults (e.g. generated tokens or long paths).
+type NoDefaultVar struct {
+	flag.Value
+}
+
+// NoDefault is a simple utility for omitting the default value of a particular flag from [PosixFlagSet.PrintDefaults]
+// output. The flag [flag.Value] for a named flag in fs (and any of its aliases, see [Alias]) will be wrapped with
+// [NoDefaultVar].
+//
+// If flag name doesn't exist in fs, panic.
+func NoDefault(fs *flag.FlagSet, name string) {
+	flg := fs.Lookup(name)
+	if flg == nil {
+		panic(fmt.Sprintf("getopt: cannot omit default of flag '%s': flag does not exist in flag set", name))
+	}
+
+	wrapped := &NoDefaultVar{flg.Value}
+
+	fs.VisitAll(func(other *flag.Flag) {
+		if other != flg && areSame(flg.Value, other.Value) {
+			other.Value = wrapped
+		}
+	})
+
+	flg.Value = wrapped
+}
+
+// NoDefault omits the default value of the flag with the given name from [PosixFlagSet.PrintDefaults] output. See
+// [NoDefault].
+func (f *PosixFlagSet) NoDefault(name string) {
+	NoDefault(f.FlagSet, name)
+}
+
+// String returns the parent [flag.Value].
+func (n *NoDefaultVar) String() string {
+	if n == nil || n.Value == nil {
+		return ""
+	}
+
+	return n.Value.String()
+}
+
+// IsBoolFlag returns true if the parent [flag.Value] is a boolean flag.
+func (n *NoDefaultVar) IsBoolFlag() bool {
+	bf, ok := unwrap(n.Value).(boolFlag)
+	return ok && bf.IsBoolFlag()
+}
+
+// Unwrap returns the parent [flag.Value].
+func (n *NoDefaultVar) Unwrap() flag.Value {
+	if n == nil {
+		return nil
+	}
+
+	return n.Value
+}
+
+// isNoDefaultFlag checks if the given flag has a [flag.Value] (or wraps a [flag.Value]) whose default value is omitted
+// from usage.
+func isNoDefaultFlag(flg *flag.Flag) bool {
+	for v := flg.Value; v != nil; {
+		if _, ok := v.(*NoDefaultVar); ok {
+			return true
+		}
+
+		w, ok := v.(wrapper)
+		if !ok {
+			return false
+		}
+
+		v = w.Unwrap()
+	}
+
+	return false
+}
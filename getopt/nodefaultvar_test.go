@@ -0,0 +1,58 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+)
+
+func TestNoDefault(t *testing.T) {
+	t.Run("should panic if flag does not exist", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("no panic")
+			}
+		}()
+
+		NoDefault(flag.NewFlagSet("test", flag.ContinueOnError), "token")
+	})
+
+	t.Run("should omit default value from usage", func(t *testing.T) {
+		var (
+			buf          bytes.Buffer
+			token, cache string
+			all          bool
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.StringVar(&token, "token", "c2VjcmV0", "api `token`")
+		Alias(fs.FlagSet, "token", "t")
+		fs.StringVar(&cache, "cache", "/var/cache/test", "cache `directory`")
+		fs.BoolVar(&all, "all", true, "show all")
+		fs.NoDefault("token")
+		NoDefault(fs.FlagSet, "all")
+
+		fs.PrintDefaults()
+
+		expected := `  --all
+      show all
+
+  --cache=<directory> (default /var/cache/test)
+      cache directory
+
+  -t <token>, --token=<token>
+      api token
+`
+		if buf.String() != expected {
+			t.Fatalf("unexpected usage string: '%s'", buf.String())
+		}
+
+		if err := fs.Parse([]string{"--all=false", "-t", "abc"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if all || token != "abc" {
+			t.Fatalf("flags not updated with expected values: %v %s", all, token)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package getopt
+
+import (
+	"fmt"
+)
+
+// Normalize registers a normalization function for the flag with the given name. During [PosixFlagSet.Parse], values
+// given to the flag are passed through fn before being set, which is useful for lowercasing, trimming or expanding
+// values.
+//
+//	fs.StringVar(&format, "format", "short", "output `format`")
+//	fs.Normalize("format", strings.ToLower)
+//
+//	--format=LONG   ->   format == "long"
+//
+// Normalization also applies to aliases of the flag (see [Alias]). Deprecated values (see
+// [PosixFlagSet.MarkValueDeprecated]) are matched against the normalized value. Registering another normalization
+// function for the same flag replaces the previous one.
+//
+// If flag name doesn't exist in f, panic.
+func (f *PosixFlagSet) Normalize(name string, fn func(string) string) {
+	if f.Lookup(name) == nil {
+		panic(fmt.Sprintf("getopt: cannot normalize flag '%s': flag does not exist in flag set", name))
+	}
+
+	if f.normalizers == nil {
+		f.normalizers = map[string]func(string) string{}
+	}
+
+	f.normalizers[name] = fn
+}
+
+// normalize passes value through the normalization function registered for the flag name (or any of its aliases).
+func (f *PosixFlagSet) normalize(name, value string) string {
+	flg := f.Lookup(name)
+	if flg == nil {
+		return value
+	}
+
+	for target, fn := range f.normalizers {
+		if tflg := f.Lookup(target); tflg != nil && areSame(flg.Value, tflg.Value) {
+			return fn(value)
+		}
+	}
+
+	return value
+}
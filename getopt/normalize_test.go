@@ -0,0 +1,70 @@
+package getopt
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Run("should panic if flag does not exist", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("no panic")
+			}
+		}()
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Normalize("format", strings.ToLower)
+	})
+
+	t.Run("should normalize values to lowercase", func(t *testing.T) {
+		var format string
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.StringVar(&format, "format", "short", "output format")
+		Alias(fs.FlagSet, "format", "f")
+		fs.Normalize("format", strings.ToLower)
+
+		if err := fs.Parse([]string{"--format=LONG"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if format != "long" {
+			t.Fatalf("format var not updated with expected value: %s", format)
+		}
+
+		if err := fs.Parse([]string{"-f", "Oneline"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if format != "oneline" {
+			t.Fatalf("format var not updated with expected value: %s", format)
+		}
+	})
+
+	t.Run("should expand home directory prefix", func(t *testing.T) {
+		var config string
+
+		home := t.TempDir()
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.StringVar(&config, "config", "", "config file")
+		fs.Normalize("config", func(value string) string {
+			if rest, ok := strings.CutPrefix(value, "~/"); ok {
+				return filepath.Join(home, rest)
+			}
+
+			return value
+		})
+
+		if err := fs.Parse([]string{"--config", "~/.config/test.json"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if config != filepath.Join(home, ".config", "test.json") {
+			t.Fatalf("config var not updated with expected value: %s", config)
+		}
+		if s := fs.Lookup("config").Value.String(); s != config {
+			t.Fatalf("unexpected string value: %s", s)
+		}
+	})
+}
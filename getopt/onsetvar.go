@@ -0,0 +1,66 @@
+package getopt
+
+import (
+	"flag"
+)
+
+// OnSetVar is a [flag.Value] which invokes a callback every time the parent [flag.Value] is set.
+type OnSetVar struct {
+	flag.Value
+
+	// Invoked with the raw value after each successful call to Set.
+	Fn func(value string)
+}
+
+// OnSet wraps v with an [OnSetVar] which invokes fn with the raw value every time v is successfully set. This is useful
+// for reacting to flags the moment they are parsed, for instance to reconfigure logging:
+//
+//	fs.TextVar(&level, "log-level", slog.LevelInfo, "log `level`")
+//
+//	flg := fs.Lookup("log-level")
+//	flg.Value = getopt.OnSet(flg.Value, func(value string) {
+//		logLevel.Set(level)
+//	})
+//
+// fn is invoked once for every call to Set (e.g. once for every occurrence of the flag at the command line), and is
+// not invoked if v rejects the value.
+func OnSet(v flag.Value, fn func(value string)) *OnSetVar {
+	return &OnSetVar{Value: v, Fn: fn}
+}
+
+// Set sets the parent [flag.Value] and invokes Fn on success.
+func (o *OnSetVar) Set(value string) error {
+	if err := o.Value.Set(value); err != nil {
+		return err
+	}
+
+	if o.Fn != nil {
+		o.Fn(value)
+	}
+
+	return nil
+}
+
+// String returns the parent [flag.Value].
+func (o *OnSetVar) String() string {
+	if o == nil || o.Value == nil {
+		return ""
+	}
+
+	return o.Value.String()
+}
+
+// IsBoolFlag returns true if the parent [flag.Value] is a boolean flag.
+func (o *OnSetVar) IsBoolFlag() bool {
+	bf, ok := unwrap(o.Value).(boolFlag)
+	return ok && bf.IsBoolFlag()
+}
+
+// Unwrap returns the parent [flag.Value].
+func (o *OnSetVar) Unwrap() flag.Value {
+	if o == nil {
+		return nil
+	}
+
+	return o.Value
+}
@@ -0,0 +1,81 @@
+package getopt
+
+import (
+	"flag"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOnSet(t *testing.T) {
+	t.Run("should invoke callback with raw value on each set", func(t *testing.T) {
+		var (
+			timeout time.Duration
+			values  []string
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.DurationVar(&timeout, "timeout", 0, "timeout")
+
+		flg := fs.Lookup("timeout")
+		flg.Value = OnSet(flg.Value, func(value string) {
+			values = append(values, value)
+		})
+		Alias(fs.FlagSet, "timeout", "t")
+
+		if err := fs.Parse([]string{"--timeout", "1m", "-t", "90s"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal([]string{"1m", "90s"}, values) {
+			t.Fatalf("unexpected callback values: %v", values)
+		}
+		if timeout != 90*time.Second {
+			t.Fatalf("unexpected flag value: %v", timeout)
+		}
+	})
+
+	t.Run("should not invoke callback if value rejected", func(t *testing.T) {
+		var (
+			count  int
+			called bool
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.IntVar(&count, "count", 0, "count")
+
+		flg := fs.Lookup("count")
+		flg.Value = OnSet(flg.Value, func(value string) {
+			called = true
+		})
+
+		if err := fs.Parse([]string{"--count", "banana"}); err == nil {
+			t.Fatalf("expected error but was nil")
+		}
+		if called {
+			t.Fatalf("callback invoked for rejected value")
+		}
+	})
+
+	t.Run("should preserve boolean flags", func(t *testing.T) {
+		var (
+			verbose bool
+			values  []string
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.BoolVar(&verbose, "verbose", false, "verbose")
+
+		flg := fs.Lookup("verbose")
+		flg.Value = OnSet(flg.Value, func(value string) {
+			values = append(values, value)
+		})
+
+		if err := fs.Parse([]string{"--verbose"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !verbose || !slices.Equal([]string{"true"}, values) {
+			t.Fatalf("unexpected state: %v %v", verbose, values)
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package getopt
+
+import (
+	"errors"
+	"flag"
+)
+
+// ParseError is an error returned by [PosixFlagSet.Parse] when an argument cannot be parsed. ParseError describes the
+// offending argument and wraps the underlying error, which can be inspected with [errors.Is], [errors.As] and
+// [errors.Unwrap]:
+//
+//	var parseErr *getopt.ParseError
+//	if errors.As(err, &parseErr) {
+//		fmt.Fprintf(os.Stderr, "bad argument #%d: %s\n", parseErr.Index, parseErr.Arg)
+//	}
+type ParseError struct {
+	// The index of the offending argument in the arguments given to Parse. Arguments read from response files share the
+	// index of the '@file' argument.
+	Index int
+
+	// The offending argument.
+	Arg string
+
+	// Where the offending argument came from (e.g. 'args.txt:12'), or empty if unknown. See
+	// [PosixFlagSet.SetArgSource].
+	Source string
+
+	// The underlying error.
+	Err error
+}
+
+// Error returns the message of the underlying error, prefixed with the source of the argument (if known).
+func (e *ParseError) Error() string {
+	if e.Source == "" {
+		return e.Err.Error()
+	}
+
+	return e.Source + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// SetArgSource configures fn to describe where the argument at index i of the arguments given to
+// [PosixFlagSet.Parse] came from (e.g. 'args.txt:12'). The description is recorded in the [ParseError] Source and
+// prefixes the error message. fn may return an empty string if the source of an argument is unknown.
+//
+// This is useful when arguments are read from a file by the application itself:
+//
+//	fs.SetArgSource(func(i int) string {
+//		return fmt.Sprintf("args.txt:%d", lines[i])
+//	})
+//
+// Arguments read from response files (see ResponseFiles) are described automatically.
+func (f *PosixFlagSet) SetArgSource(fn func(i int) string) {
+	f.argSource = fn
+}
+
+// argOrigin describes where an argument given to [PosixFlagSet.Parse] came from.
+type argOrigin struct {
+	index  int
+	source string
+}
+
+// error wraps err, caused by the argument arg, in a [ParseError]. [flag.ErrHelp] is returned as-is.
+func (o argOrigin) error(arg string, err error) error {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+
+	return &ParseError{
+		Index:  o.index,
+		Arg:    arg,
+		Source: o.source,
+		Err:    err,
+	}
+}
@@ -0,0 +1,98 @@
+package getopt
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	t.Run("should describe offending argument", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.Int("count", 0, "count")
+
+		err := fs.Parse([]string{"--count", "1", "--count=abc"})
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("expected parse error but was: %v", err)
+		}
+		if parseErr.Index != 2 || parseErr.Arg != "--count=abc" || parseErr.Source != "" {
+			t.Fatalf("unexpected parse error: %+v", parseErr)
+		}
+		if err.Error() != parseErr.Err.Error() {
+			t.Fatalf("unexpected error message: %v", err)
+		}
+	})
+
+	t.Run("should prefix errors with argument source", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.Bool("v", false, "verbose")
+		fs.SetArgSource(func(i int) string {
+			return fmt.Sprintf("args.txt:%d", i+10)
+		})
+
+		err := fs.Parse([]string{"-v", "--unknown"})
+		if err == nil || err.Error() != "args.txt:11: flag '--unknown' does not exist" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should prefix errors with response file line", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "args.txt")
+		if err := os.WriteFile(path, []byte("-v\n\n--count 'not\na number'\n"), 0o600); err != nil {
+			t.Fatalf("failed to write response file: %v", err)
+		}
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.ResponseFiles = true
+		fs.Bool("v", false, "verbose")
+		fs.Int("count", 0, "count")
+
+		err := fs.Parse([]string{"-v", "@" + path})
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("expected parse error but was: %v", err)
+		}
+		if parseErr.Index != 1 || parseErr.Arg != "--count" || parseErr.Source != path+":3" {
+			t.Fatalf("unexpected parse error: %+v", parseErr)
+		}
+		if !strings.HasPrefix(err.Error(), path+":3: invalid value 'not\na number' for flag '--count'") {
+			t.Fatalf("unexpected error message: %v", err)
+		}
+	})
+
+	t.Run("should describe each unknown flag", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.ContinueOnUnknown = true
+		fs.SetArgSource(func(i int) string {
+			return fmt.Sprintf("arg %d", i)
+		})
+
+		err := fs.Parse([]string{"--a", "--b"})
+		if err == nil || err.Error() != "arg 0: flag '--a' does not exist\narg 1: flag '--b' does not exist" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should not wrap ErrHelp", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+
+		err := fs.Parse([]string{"-h"})
+
+		var parseErr *ParseError
+		if !errors.Is(err, flag.ErrHelp) || errors.As(err, &parseErr) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
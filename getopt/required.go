@@ -0,0 +1,260 @@
+package getopt
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+)
+
+// requiredVar is a [flag.Value] carrying the requirements of a flag (see [MarkRequired], [MarkRequiredIf],
+// [MarkMinOccurrences] and [MarkMaxOccurrences]). Requirements are recorded on the [flag.Value] rather than the
+// [PosixFlagSet], so they are enforced by any [PosixFlagSet] wrapping the [flag.FlagSet] of the flag.
+type requiredVar struct {
+	flag.Value
+
+	// whether the flag must be set
+	required bool
+
+	// names of flags which make the flag required when set
+	requiredIf []string
+
+	// minimum and maximum number of occurrences of the flag (negative if unbounded)
+	minOccurrences int
+	maxOccurrences int
+}
+
+// MarkRequired marks the flag with the given name in fs as required. After parsing arguments, [PosixFlagSet.Parse]
+// returns an error for every required flag which was not set at the command line.
+//
+// A required flag is satisfied if the flag or any of its aliases is set (see [Alias]). Required flags are not checked
+// if parsing fails or help was requested ([flag.ErrHelp]).
+//
+// The requirement is recorded on the [flag.Value] of the flag, so it is enforced by any [PosixFlagSet] wrapping fs.
+// This is useful when flags are registered on a [flag.FlagSet] that is parsed elsewhere:
+//
+//	func (c *MyCommand) InitializeFlags(fs *flag.FlagSet) {
+//		fs.StringVar(&c.output, "output", "", "output `file`")
+//		getopt.MarkRequired(fs, "output")
+//	}
+//
+// If flag name doesn't exist in fs, panic.
+func MarkRequired(fs *flag.FlagSet, name string) {
+	flg := fs.Lookup(name)
+	if flg == nil {
+		panic(fmt.Sprintf("getopt: cannot mark flag '%s' as required: flag does not exist in flag set", name))
+	}
+
+	requirements(fs, flg).required = true
+}
+
+// MarkRequired marks the flag with the given name as required. See [MarkRequired].
+func (f *PosixFlagSet) MarkRequired(name string) {
+	MarkRequired(f.FlagSet, name)
+}
+
+// MarkRequiredIf marks the flag with the given name in fs as required if the flag named ifName is set. After parsing
+// arguments, [PosixFlagSet.Parse] returns an error for every such flag which was not set at the command line:
+//
+//	getopt.MarkRequiredIf(fs, "subresource", "server-side")
+//
+// As with [MarkRequired], flags are satisfied (and trigger requirements) if set directly or through aliases, and the
+// requirement is enforced by any [PosixFlagSet] wrapping fs.
+//
+// If flag name or ifName doesn't exist in fs, panic.
+func MarkRequiredIf(fs *flag.FlagSet, name, ifName string) {
+	flg := fs.Lookup(name)
+	if flg == nil {
+		panic(fmt.Sprintf("getopt: cannot mark flag '%s' as required: flag does not exist in flag set", name))
+	}
+	if fs.Lookup(ifName) == nil {
+		panic(fmt.Sprintf("getopt: cannot mark flag '%s' as required if '%s' set: flag does not exist in flag set",
+			name, ifName))
+	}
+
+	r := requirements(fs, flg)
+	r.requiredIf = append(r.requiredIf, ifName)
+}
+
+// MarkRequiredIf marks the flag with the given name as required if the flag named ifName is set. See
+// [MarkRequiredIf].
+func (f *PosixFlagSet) MarkRequiredIf(name, ifName string) {
+	MarkRequiredIf(f.FlagSet, name, ifName)
+}
+
+// MarkMinOccurrences requires the flag with the given name in fs to be given at least n times at the command line.
+// After parsing arguments, [PosixFlagSet.Parse] returns an error if the flag was given fewer times. This is useful for
+// repeatable flags, such as slice flags (see [StringsVar]):
+//
+//	fs.Var(getopt.Strings(&files), "f", "input `file`")
+//	getopt.MarkMinOccurrences(fs, "f", 1)
+//
+// Occurrences of the flag through any of its aliases (see [Alias]) are counted together. As with [MarkRequired], the
+// requirement is enforced by any [PosixFlagSet] wrapping fs.
+//
+// If flag name doesn't exist in fs, panic.
+func MarkMinOccurrences(fs *flag.FlagSet, name string, n int) {
+	flg := fs.Lookup(name)
+	if flg == nil {
+		panic(fmt.Sprintf("getopt: cannot set minimum occurrences of flag '%s': flag does not exist in flag set", name))
+	}
+
+	requirements(fs, flg).minOccurrences = n
+}
+
+// MarkMinOccurrences requires the flag with the given name to be given at least n times at the command line. See
+// [MarkMinOccurrences].
+func (f *PosixFlagSet) MarkMinOccurrences(name string, n int) {
+	MarkMinOccurrences(f.FlagSet, name, n)
+}
+
+// MarkMaxOccurrences permits the flag with the given name in fs to be given at most n times at the command line. After
+// parsing arguments, [PosixFlagSet.Parse] returns an error if the flag was given more times. See also
+// [MarkMinOccurrences].
+//
+// If flag name doesn't exist in fs, panic.
+func MarkMaxOccurrences(fs *flag.FlagSet, name string, n int) {
+	flg := fs.Lookup(name)
+	if flg == nil {
+		panic(fmt.Sprintf("getopt: cannot set maximum occurrences of flag '%s': flag does not exist in flag set", name))
+	}
+
+	requirements(fs, flg).maxOccurrences = n
+}
+
+// MarkMaxOccurrences permits the flag with the given name to be given at most n times at the command line. See
+// [MarkMaxOccurrences].
+func (f *PosixFlagSet) MarkMaxOccurrences(name string, n int) {
+	MarkMaxOccurrences(f.FlagSet, name, n)
+}
+
+// String returns the parent [flag.Value].
+func (r *requiredVar) String() string {
+	if r == nil || r.Value == nil {
+		return ""
+	}
+
+	return r.Value.String()
+}
+
+// IsBoolFlag returns true if the parent [flag.Value] is a boolean flag.
+func (r *requiredVar) IsBoolFlag() bool {
+	bf, ok := unwrap(r.Value).(boolFlag)
+	return ok && bf.IsBoolFlag()
+}
+
+// Unwrap returns the parent [flag.Value].
+func (r *requiredVar) Unwrap() flag.Value {
+	if r == nil {
+		return nil
+	}
+
+	return r.Value
+}
+
+// requirements returns the requirements of flg (or any of its aliases) in fs. If the flag has no requirements yet, the
+// [flag.Value] of flg is wrapped with a new requiredVar.
+func requirements(fs *flag.FlagSet, flg *flag.Flag) *requiredVar {
+	var r *requiredVar
+
+	fs.VisitAll(func(other *flag.Flag) {
+		if r == nil && areSame(flg.Value, other.Value) {
+			r = lookupRequirements(other)
+		}
+	})
+
+	if r == nil {
+		r = &requiredVar{Value: flg.Value, maxOccurrences: -1}
+		flg.Value = r
+	}
+
+	return r
+}
+
+// lookupRequirements returns the requiredVar wrapped by the [flag.Value] of flg, or nil if flg has no requirements.
+func lookupRequirements(flg *flag.Flag) *requiredVar {
+	for v := flg.Value; v != nil; {
+		if r, ok := v.(*requiredVar); ok {
+			return r
+		}
+
+		w, ok := v.(wrapper)
+		if !ok {
+			return nil
+		}
+
+		v = w.Unwrap()
+	}
+
+	return nil
+}
+
+// isRequired checks if flg (or any of its aliases) in f is marked as required (see [MarkRequired]).
+func (f *PosixFlagSet) isRequired(flg *flag.Flag) bool {
+	var required bool
+
+	f.VisitAll(func(other *flag.Flag) {
+		if r := lookupRequirements(other); r != nil && areSame(flg.Value, other.Value) {
+			required = required || r.required
+		}
+	})
+
+	return required
+}
+
+// countOccurrences returns the number of times the flag with the given name was set while parsing, either directly or
+// through any of its aliases.
+func (f *PosixFlagSet) countOccurrences(name string) int {
+	var (
+		flg   = f.Lookup(name)
+		count int
+	)
+
+	for other, n := range f.occurrences {
+		if areSame(flg.Value, f.Lookup(other).Value) {
+			count += n
+		}
+	}
+
+	return count
+}
+
+// CheckRequired returns an error for every required flag (see [MarkRequired] and [MarkRequiredIf]) which was not set,
+// and for every flag given too few or too many times (see [MarkMinOccurrences] and [MarkMaxOccurrences]). Parse calls
+// CheckRequired after parsing arguments, unless DeferRequired is set.
+func (f *PosixFlagSet) CheckRequired() error {
+	var (
+		errs []error
+		seen []*requiredVar
+	)
+
+	f.VisitAll(func(flg *flag.Flag) {
+		r := lookupRequirements(flg)
+		if r == nil || slices.Contains(seen, r) {
+			return
+		}
+
+		seen = append(seen, r)
+
+		if r.required && !f.Changed(flg.Name) {
+			errs = append(errs, fmt.Errorf("missing required flag '%s'", display(f.LookupCanonical(flg.Name))))
+		}
+
+		for _, ifName := range r.requiredIf {
+			if f.Changed(ifName) && !f.Changed(flg.Name) {
+				errs = append(errs, fmt.Errorf("missing flag '%s', required when flag '%s' is set",
+					display(f.LookupCanonical(flg.Name)), display(f.Lookup(ifName))))
+			}
+		}
+
+		if n := f.countOccurrences(flg.Name); n < r.minOccurrences {
+			errs = append(errs, fmt.Errorf("flag '%s' given %d times, must be given at least %d times",
+				display(f.LookupCanonical(flg.Name)), n, r.minOccurrences))
+		} else if r.maxOccurrences >= 0 && n > r.maxOccurrences {
+			errs = append(errs, fmt.Errorf("flag '%s' given %d times, must be given at most %d times",
+				display(f.LookupCanonical(flg.Name)), n, r.maxOccurrences))
+		}
+	})
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,165 @@
+package getopt
+
+import (
+	"errors"
+	"flag"
+	"testing"
+)
+
+func TestRequired(t *testing.T) {
+	t.Run("should panic if flag does not exist", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("no panic")
+			}
+		}()
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.MarkRequired("output")
+	})
+
+	t.Run("should return error if required flag not set", func(t *testing.T) {
+		var output, format string
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.StringVar(&output, "output", "-", "output file")
+		fs.StringVar(&format, "f", "", "output format")
+		fs.MarkRequired("output")
+		fs.MarkRequired("f")
+
+		err := fs.Parse([]string{"arg"})
+		if err == nil || err.Error() != "missing required flag '-f'\nmissing required flag '--output'" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should not return error if required flag set", func(t *testing.T) {
+		var output string
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.StringVar(&output, "output", "-", "output file")
+		fs.MarkRequired("output")
+
+		if err := fs.Parse([]string{"--output", "-"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should be satisfied by alias", func(t *testing.T) {
+		var output string
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.StringVar(&output, "output", "-", "output file")
+		Alias(fs.FlagSet, "output", "o")
+		fs.MarkRequired("output")
+
+		if err := fs.Parse([]string{"-o", "out.txt"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should return ErrHelp before checking required flags", func(t *testing.T) {
+		var output string
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.StringVar(&output, "output", "-", "output file")
+		fs.MarkRequired("output")
+
+		if err := fs.Parse([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestRequiredIf(t *testing.T) {
+	flags := func() *PosixFlagSet {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.Bool("server-side", false, "server-side apply")
+		fs.String("subresource", "", "subresource")
+		Alias(fs.FlagSet, "subresource", "s")
+		fs.MarkRequiredIf("subresource", "server-side")
+
+		return fs
+	}
+
+	t.Run("should panic if flag does not exist", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("no panic")
+			}
+		}()
+
+		fs := flags()
+		fs.MarkRequiredIf("subresource", "missing")
+	})
+
+	t.Run("should return error if condition violated", func(t *testing.T) {
+		err := flags().Parse([]string{"--server-side"})
+		if err == nil || err.Error() != "missing flag '--subresource', required when flag '--server-side' is set" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should not return error if condition satisfied", func(t *testing.T) {
+		if err := flags().Parse([]string{"--server-side", "-s", "status"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should not return error if condition not triggered", func(t *testing.T) {
+		if err := flags().Parse([]string{"arg"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestOccurrences(t *testing.T) {
+	parse := func(args ...string) error {
+		var files []string
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.Var(Strings(&files), "file", "input file")
+		Alias(fs.FlagSet, "file", "f")
+		MarkMinOccurrences(fs.FlagSet, "file", 1)
+		fs.MarkMaxOccurrences("file", 2)
+
+		return fs.Parse(args)
+	}
+
+	t.Run("should panic if flag does not exist", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("no panic")
+			}
+		}()
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.MarkMinOccurrences("file", 1)
+	})
+
+	t.Run("should return error if flag given too few times", func(t *testing.T) {
+		err := parse("arg")
+		if err == nil || err.Error() != "flag '--file' given 0 times, must be given at least 1 times" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should not return error if flag given within bounds", func(t *testing.T) {
+		for _, args := range [][]string{{"--file", "a"}, {"-f", "a", "--file", "b"}} {
+			if err := parse(args...); err != nil {
+				t.Fatalf("unexpected error for %v: %v", args, err)
+			}
+		}
+	})
+
+	t.Run("should return error if flag given too many times", func(t *testing.T) {
+		err := parse("-f", "a", "--file", "b", "-f", "c")
+		if err == nil || err.Error() != "flag '--file' given 3 times, must be given at most 2 times" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package getopt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// maxResponseFileDepth is the maximum nesting depth of response files referencing other response files.
+const maxResponseFileDepth = 10
+
+// readResponseFile reads the arguments from the response file at path, expanding references to other response files
+// up to the given nesting depth. For each argument, the location it was read from is returned as well (e.g.
+// 'args.txt:12').
+func readResponseFile(path string, depth int) ([]string, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read response file '%s': %w", path, err)
+	}
+
+	tokens, lines, err := splitArgs(string(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("malformed response file '%s': %w", path, err)
+	}
+
+	var arguments, sources []string
+
+	for i, token := range tokens {
+		nested, ok := strings.CutPrefix(token, "@")
+		if !ok || nested == "" {
+			arguments = append(arguments, token)
+			sources = append(sources, fmt.Sprintf("%s:%d", path, lines[i]))
+			continue
+		}
+
+		if depth == 0 {
+			return nil, nil, fmt.Errorf("response file '%s' exceeds maximum nesting depth of %d", nested,
+				maxResponseFileDepth)
+		}
+
+		expanded, expandedSources, err := readResponseFile(nested, depth-1)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		arguments = append(arguments, expanded...)
+		sources = append(sources, expandedSources...)
+	}
+
+	return arguments, sources, nil
+}
+
+// SplitArgs splits data into arguments, as done for the contents of response files (see [PosixFlagSet.Parse]).
+// Arguments are separated by whitespace (including newlines). Single quotes preserve the literal value of enclosed
+// characters. Double quotes do the same, except for backslash escapes of double quotes and backslashes. Outside of
+// quotes, a backslash escapes the following character.
+//
+//	--output "my file.txt" -v   ->   ["--output", "my file.txt", "-v"]
+//
+// Returns an error if data contains an unterminated quote or escape.
+func SplitArgs(data string) ([]string, error) {
+	tokens, _, err := splitArgs(data)
+	return tokens, err
+}
+
+// splitArgs splits data into arguments like [SplitArgs], additionally returning the (1-based) line number on which each
+// argument starts.
+func splitArgs(data string) ([]string, []int, error) {
+	var (
+		tokens  []string
+		lines   []int
+		current strings.Builder
+		inToken bool
+		quote   rune
+		escaped bool
+		line    = 1
+		start   = 1
+	)
+
+	for _, r := range data {
+		if !inToken {
+			start = line
+		}
+		if r == '\n' {
+			line++
+		}
+
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' {
+				current.WriteRune('\\')
+			}
+
+			current.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' {
+				escaped = true
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inToken = r, true
+		case r == '\\':
+			escaped, inToken = true, true
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, current.String())
+				lines = append(lines, start)
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, nil, fmt.Errorf("unterminated quote or escape")
+	}
+
+	if inToken {
+		tokens = append(tokens, current.String())
+		lines = append(lines, start)
+	}
+
+	return tokens, lines, nil
+}
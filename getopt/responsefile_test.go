@@ -0,0 +1,122 @@
+package getopt
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResponseFiles(t *testing.T) {
+	write := func(t *testing.T, name, contents string) string {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatalf("failed to write response file: %v", err)
+		}
+
+		return path
+	}
+
+	t.Run("should splice arguments from response files", func(t *testing.T) {
+		var (
+			output  string
+			verbose bool
+			count   int
+		)
+
+		nested := write(t, "nested.txt", "-c 3\n")
+		path := write(t, "args.txt", "--output \"my file.txt\"\n\t-v @"+nested+" 'extra arg'\n")
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.ResponseFiles = true
+		fs.StringVar(&output, "output", "-", "output file")
+		fs.BoolVar(&verbose, "v", false, "verbose")
+		fs.IntVar(&count, "c", 0, "count")
+
+		if err := fs.Parse([]string{"@" + path, "input.txt"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if output != "my file.txt" || !verbose || count != 3 {
+			t.Fatalf("flags not updated with expected values: %s %v %d", output, verbose, count)
+		}
+		if !slices.Equal([]string{"extra arg", "input.txt"}, fs.Args()) {
+			t.Fatalf("unexpected remaining args: %v", fs.Args())
+		}
+		if !slices.Equal([]string{"--output", "my file.txt", "-v", "-c", "3"}, fs.ConsumedArgs()) {
+			t.Fatalf("unexpected consumed args: %v", fs.ConsumedArgs())
+		}
+	})
+
+	t.Run("should not expand response files when disabled", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+
+		if err := fs.Parse([]string{"@args.txt"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal([]string{"@args.txt"}, fs.Args()) {
+			t.Fatalf("unexpected remaining args: %v", fs.Args())
+		}
+	})
+
+	t.Run("should return error if response file is missing", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.ResponseFiles = true
+
+		path := filepath.Join(t.TempDir(), "missing.txt")
+
+		err := fs.Parse([]string{"@" + path})
+		if err == nil || !strings.Contains(err.Error(), "cannot read response file '"+path+"'") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should return error if response files nest too deeply", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "loop.txt")
+		if err := os.WriteFile(path, []byte("@"+path), 0o600); err != nil {
+			t.Fatalf("failed to write response file: %v", err)
+		}
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.ResponseFiles = true
+
+		err := fs.Parse([]string{"@" + path})
+		if err == nil || !strings.Contains(err.Error(), "exceeds maximum nesting depth of 10") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestSplitArgs(t *testing.T) {
+	testcases := []struct {
+		data     string
+		expected []string
+	}{
+		{data: "", expected: nil},
+		{data: "  -a\n--b=c \r\n d", expected: []string{"-a", "--b=c", "d"}},
+		{data: `'single quoted' "double quoted"`, expected: []string{"single quoted", "double quoted"}},
+		{data: `a"b c"d ''`, expected: []string{"ab cd", ""}},
+		{data: `"say \"hi\" \n" 'it\s' a\ b`, expected: []string{`say "hi" \n`, `it\s`, "a b"}},
+	}
+
+	for _, tc := range testcases {
+		tokens, err := SplitArgs(tc.data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(tc.expected, tokens) {
+			t.Fatalf("unexpected tokens for %q: %q", tc.data, tokens)
+		}
+	}
+
+	for _, data := range []string{`"unterminated`, `'unterminated`, `trailing\`} {
+		if _, err := SplitArgs(data); err == nil {
+			t.Fatalf("expected error for %q but was nil", data)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package getopt
+
+import (
+	"time"
+)
+
+// SecondsVar is a [flag.Value] for duration flags that also accept a bare number of seconds. SecondsVar also
+// implements [flag.Getter].
+//
+// SecondsVar eases migration of legacy tools which accept durations as a number of seconds. Values with a unit suffix
+// are parsed with [time.ParseDuration], while bare numbers are interpreted as seconds (like a [UnitDurationVar] with a
+// unit of [time.Second]):
+//
+//	--timeout 30    // 30 seconds
+//	--timeout 1.5   // 1.5 seconds
+//	--timeout 30s   // 30 seconds
+//	--timeout 1m    // 1 minute
+type SecondsVar time.Duration
+
+// Seconds returns a [SecondsVar] for d.
+func Seconds(d *time.Duration) *SecondsVar {
+	return (*SecondsVar)(d)
+}
+
+// SecondsVar defines a [SecondsVar] flag with the specified name, default value and usage string. The argument p points
+// to a [time.Duration] variable in which to store the value of the flag.
+func (f *PosixFlagSet) SecondsVar(p *time.Duration, name string, value time.Duration, usage string) {
+	*p = value
+	f.Var(Seconds(p), name, usage)
+}
+
+// String returns the duration, formatted by [time.Duration.String].
+func (s SecondsVar) String() string {
+	return time.Duration(s).String()
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be a number of seconds or a duration parseable by
+// [time.ParseDuration]. Numbers overflowing a [time.Duration] are rejected.
+func (s *SecondsVar) Set(value string) error {
+	d, err := parseUnitDuration(value, time.Second)
+	if err == nil {
+		*s = SecondsVar(d)
+	}
+
+	return err
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a
+// [time.Duration].
+func (s *SecondsVar) Get() any {
+	return time.Duration(*s)
+}
@@ -0,0 +1,77 @@
+package getopt
+
+import (
+	"flag"
+	"testing"
+	"time"
+)
+
+func TestSecondsVar(t *testing.T) {
+	t.Run("should parse bare numbers as seconds and durations", func(t *testing.T) {
+		testcases := []struct {
+			arg      string
+			expected time.Duration
+		}{
+			{arg: "30", expected: 30 * time.Second},
+			{arg: "30s", expected: 30 * time.Second},
+			{arg: "1m", expected: time.Minute},
+			{arg: "1.5", expected: 1500 * time.Millisecond},
+			{arg: "0", expected: 0},
+			{arg: "-2", expected: -2 * time.Second},
+		}
+
+		for _, tc := range testcases {
+			var timeout time.Duration
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.SecondsVar(&timeout, "timeout", 10*time.Second, "request timeout")
+
+			if err := fs.Parse([]string{"--timeout", tc.arg}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if timeout != tc.expected {
+				t.Fatalf("unexpected result for '%s': %v", tc.arg, timeout)
+			}
+		}
+	})
+
+	t.Run("should set default value", func(t *testing.T) {
+		var timeout time.Duration
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SecondsVar(&timeout, "timeout", 10*time.Second, "request timeout")
+
+		if timeout != 10*time.Second {
+			t.Fatalf("unexpected default value: %v", timeout)
+		}
+		if def := fs.Lookup("timeout").DefValue; def != "10s" {
+			t.Fatalf("unexpected default value: %s", def)
+		}
+	})
+
+	t.Run("should return error if malformed", func(t *testing.T) {
+		var timeout time.Duration
+
+		if err := Seconds(&timeout).Set("thirty"); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("should return error if out of range", func(t *testing.T) {
+		var timeout time.Duration
+
+		for _, value := range []string{"1e10", "-1e10", "9223372037"} {
+			err := Seconds(&timeout).Set(value)
+			if err == nil || err.Error() != "duration '"+value+"' out of range" {
+				t.Fatalf("unexpected error for '%s': %v", value, err)
+			}
+		}
+
+		if err := Seconds(&timeout).Set("9223372036"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if timeout != 9223372036*time.Second {
+			t.Fatalf("timeout var not updated with expected value: %v", timeout)
+		}
+	})
+}
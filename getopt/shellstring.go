@@ -0,0 +1,37 @@
+package getopt
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// shellSafe matches arguments which need no quoting in POSIX shells.
+var shellSafe = regexp.MustCompile(`^[a-zA-Z0-9_@%+=:,./-]+$`)
+
+// ShellString returns the arguments processed by [PosixFlagSet.Parse] (see [PosixFlagSet.ConsumedArgs] and
+// [PosixFlagSet.Args]) as a single string, quoted such that it can be safely pasted into a POSIX shell or logged.
+// Arguments containing whitespace or shell metacharacters are enclosed in single quotes.
+//
+//	fs.Parse([]string{"--message", "it's done", "file.txt"})
+//	fs.ShellString()   ->   --message 'it'\''s done' file.txt
+//
+// The result can be split into the original arguments with [SplitArgs].
+func (f *PosixFlagSet) ShellString() string {
+	var quoted []string
+
+	for _, arg := range slices.Concat(f.ConsumedArgs(), f.Args()) {
+		quoted = append(quoted, shellQuote(arg))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// shellQuote quotes arg for POSIX shells, if necessary.
+func shellQuote(arg string) string {
+	if shellSafe.MatchString(arg) {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
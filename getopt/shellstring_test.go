@@ -0,0 +1,55 @@
+package getopt
+
+import (
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestShellString(t *testing.T) {
+	t.Run("should quote values containing spaces and quotes", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.String("message", "", "commit message")
+		fs.Bool("v", false, "verbose")
+
+		args := []string{"-v", "--message", "it's done", "--", `say "hi"`, "file.txt", ""}
+
+		if err := fs.Parse(args); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := `-v --message 'it'\''s done' -- 'say "hi"' file.txt ''`
+		if actual := fs.ShellString(); actual != expected {
+			t.Fatalf("unexpected shell string: %s", actual)
+		}
+
+		split, err := SplitArgs(fs.ShellString())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(args, split) {
+			t.Fatalf("unexpected args after splitting: %q", split)
+		}
+	})
+
+	t.Run("should not quote safe arguments", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.String("output", "", "output file")
+
+		if err := fs.Parse([]string{"--output=./out/a.txt", "key=value"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if actual := fs.ShellString(); actual != "--output=./out/a.txt key=value" {
+			t.Fatalf("unexpected shell string: %s", actual)
+		}
+	})
+
+	t.Run("should return empty string if not parsed", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+
+		if actual := fs.ShellString(); actual != "" {
+			t.Fatalf("unexpected shell string: %s", actual)
+		}
+	})
+}
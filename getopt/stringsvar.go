@@ -64,3 +64,65 @@ func (s *StringsVar) Set(value string) error {
 func (s StringsVar) Get() any {
 	return []string(s)
 }
+
+// ResetStringsVar is like [StringsVar], but the first value given at the command line replaces the initial values of
+// the slice instead of extending them. Subsequent values are appended. ResetStringsVar also implements [flag.Getter].
+//
+// This is useful when the slice is seeded with defaults (e.g. from a configuration file) which the user should be able
+// to replace rather than merge with:
+//
+//	tags := []string{"default"}
+//	fs.Var(getopt.ResetStrings(&tags), "tag", "image `tag`")
+//
+//	--tag a --tag b   ->   [a b]
+//
+// When values are set from several sources (e.g. environment variables before the command line), call
+// [ResetStringsVar.Rearm] between sources so that each source replaces the values of the previous one rather than
+// extending them.
+type ResetStringsVar struct {
+	value *[]string
+	set   bool
+}
+
+// ResetStrings returns a [ResetStringsVar] for ss.
+func ResetStrings(ss *[]string) *ResetStringsVar {
+	return &ResetStringsVar{value: ss}
+}
+
+// String returns the slice, formatted as comma-separated values.
+func (s *ResetStringsVar) String() string {
+	if s == nil || s.value == nil {
+		return ""
+	}
+
+	return StringsVar(*s.value).String()
+}
+
+// Set fulfills the [flag.Value] interface. The first call to Set clears the initial values of the slice.
+func (s *ResetStringsVar) Set(value string) error {
+	var values []string
+	if s.set {
+		values = *s.value
+	}
+
+	if err := Strings(&values).Set(value); err != nil {
+		return err
+	}
+
+	*s.value, s.set = values, true
+
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a
+// []string.
+func (s *ResetStringsVar) Get() any {
+	return *s.value
+}
+
+// Rearm makes the next call to Set clear the values of the slice again, as done by the first call to Set. This is
+// useful when values are set from several sources in order of precedence, allowing each source to replace the values
+// set by the previous one.
+func (s *ResetStringsVar) Rearm() {
+	s.set = false
+}
@@ -1,6 +1,10 @@
 package getopt
 
-import "testing"
+import (
+	"flag"
+	"slices"
+	"testing"
+)
 
 func TestStringsVar(t *testing.T) {
 	t.Run("should not panic if calling String on nil value", func(t *testing.T) {
@@ -11,3 +15,80 @@ func TestStringsVar(t *testing.T) {
 		}
 	})
 }
+
+func TestResetStringsVar(t *testing.T) {
+	t.Run("should not panic if calling String on nil value", func(t *testing.T) {
+		var z *ResetStringsVar
+
+		if result := z.String(); result != "" {
+			t.Fatalf("unexpected result: %s", result)
+		}
+	})
+
+	t.Run("should replace initial values", func(t *testing.T) {
+		tags := []string{"default", "seeded"}
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Var(ResetStrings(&tags), "tag", "image tag")
+
+		if def := fs.Lookup("tag").DefValue; def != "default,seeded" {
+			t.Fatalf("unexpected default value: %s", def)
+		}
+
+		if err := fs.Parse([]string{"--tag", "a", "--tag=b,c"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !slices.Equal([]string{"a", "b", "c"}, tags) {
+			t.Fatalf("unexpected values: %v", tags)
+		}
+	})
+
+	t.Run("should keep initial values if not set", func(t *testing.T) {
+		tags := []string{"default"}
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Var(ResetStrings(&tags), "tag", "image tag")
+
+		if err := fs.Parse(nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !slices.Equal([]string{"default"}, tags) {
+			t.Fatalf("unexpected values: %v", tags)
+		}
+	})
+
+	t.Run("should replace values again once rearmed", func(t *testing.T) {
+		tags := []string{"default"}
+
+		v := ResetStrings(&tags)
+		for _, value := range []string{"env1", "env2"} {
+			if err := v.Set(value); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		v.Rearm()
+
+		if err := v.Set("cli"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !slices.Equal([]string{"cli"}, tags) {
+			t.Fatalf("unexpected values: %v", tags)
+		}
+	})
+
+	t.Run("should keep initial values if first value is malformed", func(t *testing.T) {
+		tags := []string{"default"}
+
+		if err := ResetStrings(&tags).Set(`"unterminated`); err == nil {
+			t.Fatalf("expected error but was nil")
+		}
+
+		if !slices.Equal([]string{"default"}, tags) {
+			t.Fatalf("unexpected values: %v", tags)
+		}
+	})
+}
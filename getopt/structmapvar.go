@@ -0,0 +1,227 @@
+package getopt
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StructMapVar is a [flag.Value] for flags that accept key=value pairs which are assigned to the fields of a struct.
+// StructMapVar also implements [flag.Getter]. This is useful for flags that override individual settings of a
+// configuration struct (e.g. '--set retries=3').
+//
+// Like [MapVar], flag values are key=value pairs which may be comma separated. Keys name exported fields of the struct,
+// either by the field 'flag' tag or by the field name (case-insensitive). Fields of nested structs are addressed with
+// dotted keys (e.g. 'server.port=8080'). Values are converted to the field type, which must be a string, bool, integer,
+// float or [time.Duration].
+//
+//	type Config struct {
+//		Retries int
+//		Verbose bool
+//		Server  struct {
+//			Host string `flag:"hostname"`
+//		}
+//	}
+//
+//	fs.Var(getopt.StructMap(&cfg), "set", "override a configuration `key=value`")
+//
+//	--set retries=3 --set verbose=true,server.hostname=localhost
+type StructMapVar struct {
+	value reflect.Value
+}
+
+// StructMap returns a [StructMapVar] for p. If p is not a non-nil pointer to a struct, panic.
+func StructMap(p any) *StructMapVar {
+	v := reflect.ValueOf(p)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("getopt: struct map value must be a pointer to a struct, got %T", p))
+	}
+
+	return &StructMapVar{value: v.Elem()}
+}
+
+// String returns the non-zero fields of the struct, formatted as a set of key-value pairs.
+func (s *StructMapVar) String() string {
+	if s == nil || !s.value.IsValid() {
+		return ""
+	}
+
+	var entries []string
+
+	walkStructFields(s.value, "", func(key string, field reflect.Value) {
+		if !field.IsZero() {
+			entries = append(entries, key+"="+formatStructField(field))
+		}
+	})
+
+	return formatMapEntries(entries)
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be a set of key-value pairs, where each key names a
+// field of the struct. If any pair is invalid, the struct is left unchanged.
+func (s *StructMapVar) Set(value string) error {
+	entries, err := parseMapEntries(value)
+	if err != nil {
+		return err
+	}
+
+	// pairs are assigned to a zero struct first, so that no field is updated unless every pair is valid
+	if err := setStructFields(reflect.New(s.value.Type()).Elem(), entries); err != nil {
+		return err
+	}
+
+	return setStructFields(s.value, entries)
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns the struct.
+func (s *StructMapVar) Get() any {
+	return s.value.Interface()
+}
+
+// setStructFields assigns the key-value pairs in entries to the fields of struct v.
+func setStructFields(v reflect.Value, entries []string) error {
+	for _, pair := range entries {
+		k, value, _ := strings.Cut(pair, "=")
+
+		field, ok := lookupStructField(v, k)
+		if !ok {
+			return fmt.Errorf("getopt: unknown key '%s'", k)
+		}
+
+		if err := setStructField(field, value); err != nil {
+			return fmt.Errorf("getopt: invalid value '%s' for key '%s': %w", value, k, err)
+		}
+	}
+
+	return nil
+}
+
+// lookupStructField returns the (settable) field of struct v with the given (possibly dotted) key. Pointers to nested
+// structs are allocated as needed.
+func lookupStructField(v reflect.Value, key string) (reflect.Value, bool) {
+	name, rest, nested := strings.Cut(key, ".")
+
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() || !strings.EqualFold(structFieldKey(sf), name) {
+			continue
+		}
+
+		field := v.Field(i)
+		if !nested {
+			return field, true
+		}
+
+		if field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+
+			field = field.Elem()
+		}
+
+		if field.Kind() != reflect.Struct {
+			return reflect.Value{}, false
+		}
+
+		return lookupStructField(field, rest)
+	}
+
+	return reflect.Value{}, false
+}
+
+// walkStructFields invokes fn for every (non-struct) exported field of struct v, recursing into nested structs.
+func walkStructFields(v reflect.Value, prefix string, fn func(key string, field reflect.Value)) {
+	for i := range v.NumField() {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		key := prefix + structFieldKey(sf)
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Pointer && field.Type().Elem().Kind() == reflect.Struct {
+			if field.IsNil() {
+				continue
+			}
+
+			field = field.Elem()
+		}
+
+		if field.Kind() == reflect.Struct {
+			walkStructFields(field, key+".", fn)
+		} else {
+			fn(key, field)
+		}
+	}
+}
+
+// structFieldKey returns the key of a struct field: the 'flag' tag, if present, otherwise the field name.
+func structFieldKey(sf reflect.StructField) string {
+	if tag, ok := sf.Tag.Lookup("flag"); ok && tag != "" {
+		return tag
+	}
+
+	return sf.Name
+}
+
+// setStructField parses value according to the type of field and assigns it.
+func setStructField(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeFor[time.Duration]() {
+		d, err := time.ParseDuration(value)
+		if err == nil {
+			field.SetInt(int64(d))
+		}
+
+		return err
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 0, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
+
+// formatStructField formats the value of field as accepted by setStructField.
+func formatStructField(field reflect.Value) string {
+	if d, ok := field.Interface().(time.Duration); ok {
+		return d.String()
+	}
+
+	return fmt.Sprint(field.Interface())
+}
@@ -0,0 +1,99 @@
+package getopt
+
+import (
+	"flag"
+	"testing"
+	"time"
+)
+
+func TestStructMapVar(t *testing.T) {
+	type server struct {
+		Host string `flag:"hostname"`
+		Port uint16
+	}
+
+	type config struct {
+		Retries int
+		Verbose bool
+		Name    string
+		Timeout time.Duration
+		Server  server
+		Proxy   *server
+
+		internal int
+	}
+
+	t.Run("should panic if not a pointer to a struct", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("no panic")
+			}
+		}()
+
+		var cfg config
+		StructMap(cfg)
+	})
+
+	t.Run("should assign fields from key-value pairs", func(t *testing.T) {
+		cfg := config{Name: "default"}
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Var(StructMap(&cfg), "set", "override a configuration `key=value`")
+
+		err := fs.Parse([]string{
+			"--set", "retries=3",
+			"--set", "VERBOSE=true,timeout=1m",
+			"--set=name=test,server.hostname=localhost,server.port=8080",
+			"--set", "proxy.port=3128",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if cfg.Retries != 3 || !cfg.Verbose || cfg.Name != "test" || cfg.Timeout != time.Minute {
+			t.Fatalf("fields not updated with expected values: %+v", cfg)
+		}
+		if cfg.Server.Host != "localhost" || cfg.Server.Port != 8080 {
+			t.Fatalf("nested fields not updated with expected values: %+v", cfg.Server)
+		}
+		if cfg.Proxy == nil || cfg.Proxy.Port != 3128 {
+			t.Fatalf("nested pointer fields not updated with expected values: %+v", cfg.Proxy)
+		}
+
+		expected := "Retries=3,Verbose=true,Name=test,Timeout=1m0s,Server.hostname=localhost,Server.Port=8080," +
+			"Proxy.Port=3128"
+		if s := fs.Lookup("set").Value.String(); s != expected {
+			t.Fatalf("unexpected string value: %s", s)
+		}
+	})
+
+	t.Run("should reject unknown keys", func(t *testing.T) {
+		var cfg config
+
+		for _, key := range []string{"unknown=1", "internal=1", "server.unknown=1", "retries.value=1", "host=a"} {
+			if err := StructMap(&cfg).Set(key); err == nil {
+				t.Fatalf("expected error for '%s' but was nil", key)
+			}
+		}
+	})
+
+	t.Run("should reject malformed values", func(t *testing.T) {
+		var cfg config
+
+		for _, value := range []string{"retries=abc", "verbose=maybe", "server.port=70000", "timeout=1"} {
+			if err := StructMap(&cfg).Set(value); err == nil {
+				t.Fatalf("expected error for '%s' but was nil", value)
+			}
+		}
+	})
+	t.Run("should not update fields if any pair is invalid", func(t *testing.T) {
+		var cfg config
+
+		if err := StructMap(&cfg).Set("retries=1,proxy.port=3128,verbose=maybe"); err == nil {
+			t.Fatalf("expected error but was nil")
+		}
+		if cfg.Retries != 0 || cfg.Proxy != nil || cfg.Verbose {
+			t.Fatalf("struct unexpectedly updated: %+v", cfg)
+		}
+	})
+}
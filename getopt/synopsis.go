@@ -0,0 +1,42 @@
+package getopt
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Synopsis returns a compact, single-line synopsis of the flags in the flag set, which is useful for generating usage
+// lines:
+//
+//	[-a] [-c <uint>] --name <string> [--output <file>]
+//
+// Flags are listed in the same order as [PosixFlagSet.PrintDefaults], and aliases (see [Alias]) are represented by the
+// shortest name of the flag. Boolean flags are rendered without an argument, while the argument of other flags is named
+// as in the usage text. Required flags (see [MarkRequired]) are rendered without brackets. Hidden flags are
+// omitted.
+func (f *PosixFlagSet) Synopsis() string {
+	var (
+		groups   = f.group()
+		synopsis []string
+	)
+
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		group := groups[key]
+
+		flg := group[0]
+		item := display(flg)
+
+		if name := unquote(flg)[0]; !isBoolFlag(flg) && name != "" {
+			item += " <" + name + ">"
+		}
+
+		if !f.isRequired(flg) {
+			item = "[" + item + "]"
+		}
+
+		synopsis = append(synopsis, item)
+	}
+
+	return strings.Join(synopsis, " ")
+}
@@ -0,0 +1,53 @@
+package getopt
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestSynopsis(t *testing.T) {
+	t.Run("should render synopsis of mixed flag set", func(t *testing.T) {
+		var (
+			all    bool
+			count  uint
+			name   string
+			output string
+			secret string
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.BoolVar(&all, "a", false, "show all")
+		fs.UintVar(&count, "count", 0, "number of results")
+		Alias(fs.FlagSet, "count", "c")
+		fs.StringVar(&name, "name", "", "resource name")
+		fs.StringVar(&output, "output", "-", "output `file`")
+		fs.StringVar(&secret, "secret", "", "secret token")
+		fs.Var(Strings(new([]string)), "label", "resource labels")
+		Hide(fs.FlagSet, "secret")
+		fs.MarkRequired("name")
+
+		expected := "[-a] [-c <uint>] [--label <value>] --name <string> [--output <file>]"
+		if synopsis := fs.Synopsis(); synopsis != expected {
+			t.Fatalf("unexpected synopsis: '%s'", synopsis)
+		}
+	})
+
+	t.Run("should treat aliases of required flags as required", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.String("file", "", "input `file`")
+		Alias(fs.FlagSet, "file", "f")
+		fs.MarkRequired("file")
+
+		if synopsis := fs.Synopsis(); synopsis != "-f <file>" {
+			t.Fatalf("unexpected synopsis: '%s'", synopsis)
+		}
+	})
+
+	t.Run("should return empty synopsis for empty flag set", func(t *testing.T) {
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+
+		if synopsis := fs.Synopsis(); synopsis != "" {
+			t.Fatalf("unexpected synopsis: '%s'", synopsis)
+		}
+	})
+}
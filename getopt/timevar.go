@@ -1,11 +1,12 @@
 package getopt
 
 import (
+	"fmt"
 	"time"
 )
 
 // TimeVar is a [flag.Value] for flags that accept timestamps in [time.RFC3339] format. TimeVar also implements
-// [flag.Getter].
+// [flag.Getter]. For timestamps in other formats, see [TimeLayoutVar].
 type TimeVar time.Time
 
 // Time returns a [TimeVar] for tm.
@@ -20,7 +21,7 @@ func (t TimeVar) String() string {
 
 // Set fulfills the [flag.Value] interface. The given value must be a correctly formatted [time.RFC3339] timestamp.
 func (t *TimeVar) Set(value string) error {
-	tm, err := time.Parse(time.RFC3339, value)
+	tm, err := parseTime(time.RFC3339, value)
 	if err == nil {
 		*t = TimeVar(tm)
 	}
@@ -33,3 +34,78 @@ func (t *TimeVar) Set(value string) error {
 func (t *TimeVar) Get() any {
 	return time.Time(*t)
 }
+
+// TimeLayoutVar is a [flag.Value] for flags that accept timestamps in a configurable layout (see [time.Layout]).
+// TimeLayoutVar also implements [flag.Getter].
+//
+//	var since time.Time
+//	fs.Var(getopt.TimeLayout(&since, time.DateOnly), "since", "show items since")
+type TimeLayoutVar struct {
+	// The timestamp updated by the flag.
+	Time *time.Time
+
+	// The layout of timestamps accepted by the flag. Defaults to [time.RFC3339] if empty.
+	Layout string
+}
+
+// TimeLayout returns a [TimeLayoutVar] for tm, accepting timestamps with the given layout. If layout is empty,
+// [time.RFC3339] is used.
+func TimeLayout(tm *time.Time, layout string) *TimeLayoutVar {
+	return &TimeLayoutVar{Time: tm, Layout: layout}
+}
+
+// String returns the timestamp formatted with the layout of the flag, or an empty string if the timestamp is the zero
+// [time.Time].
+func (t *TimeLayoutVar) String() string {
+	if t == nil || t.Time == nil || t.Time.IsZero() {
+		return ""
+	}
+
+	return t.Time.Format(t.layout())
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be a timestamp with the layout of the flag.
+func (t *TimeLayoutVar) Set(value string) error {
+	tm, err := parseTime(t.layout(), value)
+	if err == nil {
+		*t.Time = tm
+	}
+
+	return err
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a
+// [time.Time].
+func (t *TimeLayoutVar) Get() any {
+	return *t.Time
+}
+
+// Validate fulfills the [Validator] interface, checking that the timestamp can be parsed with the layout of the flag.
+func (t *TimeLayoutVar) Validate() error {
+	if value := t.String(); value != "" {
+		_, err := parseTime(t.layout(), value)
+		return err
+	}
+
+	return nil
+}
+
+// layout returns the layout of the flag, defaulting to [time.RFC3339].
+func (t *TimeLayoutVar) layout() string {
+	if t.Layout == "" {
+		return time.RFC3339
+	}
+
+	return t.Layout
+}
+
+// parseTime parses value with the given layout. Errors mention the expected layout, so users can tell why a timestamp
+// was rejected.
+func parseTime(layout, value string) (time.Time, error) {
+	tm, err := time.Parse(layout, value)
+	if err != nil {
+		return tm, fmt.Errorf("timestamp must have layout '%s': %w", layout, err)
+	}
+
+	return tm, nil
+}
@@ -1,6 +1,12 @@
 package getopt
 
-import "testing"
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestTimeVar(t *testing.T) {
 	t.Run("should not panic if calling String on nil value", func(t *testing.T) {
@@ -10,4 +16,77 @@ func TestTimeVar(t *testing.T) {
 			t.Fatalf("unexpected result: %s", result)
 		}
 	})
+
+	t.Run("should report expected layout in errors", func(t *testing.T) {
+		var z TimeVar
+
+		err := z.Set("2025-13-40")
+		if err == nil || !strings.HasPrefix(err.Error(), "timestamp must have layout '"+time.RFC3339+"': ") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestTimeLayoutVar(t *testing.T) {
+	t.Run("should not panic if calling String on nil value", func(t *testing.T) {
+		var z *TimeLayoutVar
+
+		if result := z.String(); result != "" {
+			t.Fatalf("unexpected result: %s", result)
+		}
+		if result := (&TimeLayoutVar{}).String(); result != "" {
+			t.Fatalf("unexpected result: %s", result)
+		}
+	})
+
+	t.Run("should parse timestamps with layout", func(t *testing.T) {
+		var since, until time.Time
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Var(TimeLayout(&since, time.DateOnly), "since", "show items since")
+		fs.Var(TimeLayout(&until, ""), "until", "show items until")
+
+		if err := fs.Parse([]string{"--since", "2025-01-02", "--until", "2025-02-01T10:00:00Z"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !since.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) {
+			t.Fatalf("unexpected since: %v", since)
+		}
+		if !until.Equal(time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)) {
+			t.Fatalf("unexpected until: %v", until)
+		}
+		if v, ok := fs.GetValue("since"); !ok || v != since {
+			t.Fatalf("unexpected value: %v", v)
+		}
+	})
+
+	t.Run("should report expected layout in errors", func(t *testing.T) {
+		var since time.Time
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.Var(TimeLayout(&since, time.DateOnly), "since", "show items since")
+
+		err := fs.Parse([]string{"--since", "2025-13-40"})
+		if err == nil || !strings.Contains(err.Error(), "timestamp must have layout '2006-01-02'") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("should render time placeholder", func(t *testing.T) {
+		var (
+			buf   bytes.Buffer
+			since time.Time
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.Var(TimeLayout(&since, time.DateOnly), "since", "show items since")
+
+		fs.PrintDefaults()
+
+		if expected := "  --since=<time>\n      show items since\n"; buf.String() != expected {
+			t.Fatalf("unexpected usage: %q", buf.String())
+		}
+	})
 }
@@ -0,0 +1,88 @@
+package getopt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// UnitDurationVar is a [flag.Value] for duration flags that also accept a bare number of a configurable unit.
+// UnitDurationVar also implements [flag.Getter].
+//
+// Values with a unit suffix are parsed with [time.ParseDuration], while bare numbers are multiplied by Unit. For
+// instance, with a Unit of [time.Millisecond]:
+//
+//	--interval 500     // 500 milliseconds
+//	--interval 2.5     // 2.5 milliseconds
+//	--interval 500ms   // 500 milliseconds
+//	--interval 2s      // 2 seconds
+//
+// See also [SecondsVar].
+type UnitDurationVar struct {
+	// The duration updated by the flag.
+	Duration *time.Duration
+
+	// The unit of bare numbers.
+	Unit time.Duration
+}
+
+// UnitDuration returns a [UnitDurationVar] for d, interpreting bare numbers in the given unit.
+func UnitDuration(d *time.Duration, unit time.Duration) *UnitDurationVar {
+	return &UnitDurationVar{Duration: d, Unit: unit}
+}
+
+// UnitDurationVar defines a [UnitDurationVar] flag with the specified name, unit, default value and usage string. The
+// argument p points to a [time.Duration] variable in which to store the value of the flag.
+func (f *PosixFlagSet) UnitDurationVar(p *time.Duration, name string, unit, value time.Duration, usage string) {
+	*p = value
+	f.Var(UnitDuration(p, unit), name, usage)
+}
+
+// String returns the duration, formatted by [time.Duration.String].
+func (u *UnitDurationVar) String() string {
+	if u == nil || u.Duration == nil {
+		return time.Duration(0).String()
+	}
+
+	return u.Duration.String()
+}
+
+// Set fulfills the [flag.Value] interface. The given value must be a number of Unit or a duration parseable by
+// [time.ParseDuration]. Numbers overflowing a [time.Duration] are rejected.
+func (u *UnitDurationVar) Set(value string) error {
+	d, err := parseUnitDuration(value, u.Unit)
+	if err == nil {
+		*u.Duration = d
+	}
+
+	return err
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns a
+// [time.Duration].
+func (u *UnitDurationVar) Get() any {
+	return *u.Duration
+}
+
+// Validate fulfills the [Validator] interface, checking that the duration can be parsed in the unit of the flag.
+func (u *UnitDurationVar) Validate() error {
+	_, err := parseUnitDuration(u.String(), u.Unit)
+	return err
+}
+
+// parseUnitDuration parses value as a number of unit or, if value isn't a number, with [time.ParseDuration]. Returns an
+// error if the number of unit overflows a [time.Duration].
+func parseUnitDuration(value string, unit time.Duration) (time.Duration, error) {
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return time.ParseDuration(value)
+	}
+
+	d := n * float64(unit)
+	if d < math.MinInt64 || d >= math.MaxInt64 {
+		return 0, fmt.Errorf("duration '%s' out of range", value)
+	}
+
+	return time.Duration(d), nil
+}
@@ -0,0 +1,79 @@
+package getopt
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+	"time"
+)
+
+func TestUnitDurationVar(t *testing.T) {
+	t.Run("should parse bare numbers in unit and durations", func(t *testing.T) {
+		testcases := []struct {
+			unit     time.Duration
+			arg      string
+			expected time.Duration
+		}{
+			{unit: time.Millisecond, arg: "500", expected: 500 * time.Millisecond},
+			{unit: time.Millisecond, arg: "2.5", expected: 2500 * time.Microsecond},
+			{unit: time.Millisecond, arg: "500ms", expected: 500 * time.Millisecond},
+			{unit: time.Millisecond, arg: "2s", expected: 2 * time.Second},
+			{unit: time.Minute, arg: "5", expected: 5 * time.Minute},
+			{unit: time.Minute, arg: "-1", expected: -time.Minute},
+			{unit: time.Minute, arg: "90s", expected: 90 * time.Second},
+			{unit: time.Hour, arg: "0", expected: 0},
+		}
+
+		for _, tc := range testcases {
+			var interval time.Duration
+
+			fs := NewPosixFlagSet("test", flag.ContinueOnError)
+			fs.UnitDurationVar(&interval, "interval", tc.unit, time.Second, "poll interval")
+
+			if err := fs.Parse([]string{"--interval", tc.arg}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if interval != tc.expected {
+				t.Fatalf("unexpected result for '%s' (unit %v): %v", tc.arg, tc.unit, interval)
+			}
+			if v, ok := fs.GetValue("interval"); !ok || v != tc.expected {
+				t.Fatalf("unexpected value: %v", v)
+			}
+		}
+	})
+
+	t.Run("should set default value", func(t *testing.T) {
+		var (
+			buf      bytes.Buffer
+			interval time.Duration
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&buf)
+		fs.UnitDurationVar(&interval, "interval", time.Millisecond, 250*time.Millisecond, "poll `interval`")
+
+		if interval != 250*time.Millisecond {
+			t.Fatalf("unexpected default: %v", interval)
+		}
+
+		fs.PrintDefaults()
+
+		if expected := "  --interval=<interval> (default 250ms)\n      poll interval\n"; buf.String() != expected {
+			t.Fatalf("unexpected usage: %q", buf.String())
+		}
+	})
+
+	t.Run("should return error for malformed values", func(t *testing.T) {
+		var interval time.Duration
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.UnitDurationVar(&interval, "interval", time.Millisecond, 0, "poll interval")
+
+		for _, arg := range []string{"abc", "5 ms", "Inf", "NaN", "1e13", "-1e13"} {
+			if err := fs.Parse([]string{"--interval", arg}); err == nil {
+				t.Fatalf("expected error for '%s' but was nil", arg)
+			}
+		}
+	})
+}
@@ -0,0 +1,170 @@
+package getopt
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Validator is implemented by [flag.Value] types which can check their current value, for instance values restricted
+// to a range or set of values (e.g. [EnumVar]). See [PosixFlagSet.ValidateCurrent].
+type Validator interface {
+	// Validate returns an error if the current value is invalid.
+	Validate() error
+}
+
+// ValidateCurrent checks the current value of every flag in the flag set without parsing arguments, returning an error
+// for every invalid value. This is useful when flag values are updated from untrusted sources bypassing
+// [PosixFlagSet.Parse] (e.g. assigned from a configuration file):
+//
+//	if err := fs.ValidateCurrent(); err != nil {
+//		return fmt.Errorf("invalid configuration: %w", err)
+//	}
+//
+// Flags whose [flag.Value] (or any wrapped [flag.Value]) implements [Validator] are checked with Validate. Otherwise,
+// the current value is round-tripped through Set(String()) with a copy of the [flag.Value], leaving the flag untouched.
+// Empty values and values which cannot be copied safely (e.g. structs referencing the variable updated by the flag)
+// are not checked. Aliases (see [Alias]) are checked once.
+func (f *PosixFlagSet) ValidateCurrent() error {
+	var (
+		errs []error
+		seen []flag.Value
+	)
+
+	f.VisitAll(func(flg *flag.Flag) {
+		for _, v := range seen {
+			if areSame(v, flg.Value) {
+				return
+			}
+		}
+
+		seen = append(seen, flg.Value)
+
+		if err := validateValue(flg.Value); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value '%s' for flag '%s': %w", flg.Value, display(flg), err))
+		}
+	})
+
+	return errors.Join(errs...)
+}
+
+// validateValue checks the current value of v with the [Validator] of v (or any wrapped [flag.Value]). Otherwise, the
+// non-empty value is round-tripped through a copy of v. Values which cannot be copied safely are considered valid.
+func validateValue(v flag.Value) error {
+	for w := v; w != nil; {
+		if validator, ok := w.(Validator); ok {
+			return validator.Validate()
+		}
+
+		wrapped, ok := w.(wrapper)
+		if !ok {
+			break
+		}
+
+		w = wrapped.Unwrap()
+	}
+
+	// empty values (e.g. empty slices and maps) are not round-tripped
+	if v.String() == "" {
+		return nil
+	}
+
+	var (
+		value  = reflect.ValueOf(unwrap(v))
+		copied reflect.Value
+	)
+
+	switch value.Kind() {
+	case reflect.Pointer:
+		// structs may share state with the flag (e.g. a pointer to the variable updated by the flag)
+		if value.IsNil() || value.Elem().Kind() == reflect.Struct {
+			return nil
+		}
+
+		// slices are left empty, so appending values doesn't write to the backing array of the flag
+		copied = reflect.New(value.Type().Elem())
+		if value.Elem().Kind() != reflect.Slice {
+			copied.Elem().Set(value.Elem())
+		}
+	case reflect.Map:
+		copied = reflect.MakeMap(value.Type())
+	default:
+		return nil
+	}
+
+	fv, ok := copied.Interface().(flag.Value)
+	if !ok {
+		return nil
+	}
+
+	return fv.Set(v.String())
+}
+
+// IntVarFunc defines an int flag with the specified name, default value and usage string. The argument p points to an
+// int variable in which to store the value of the flag.
+//
+// Once a value is successfully parsed, it is passed to validate. If validate returns an error, the value is rejected
+// and [PosixFlagSet.Parse] fails with an error naming the flag. This is useful for bounds checking:
+//
+//	fs.IntVarFunc(&port, "port", 8080, "listen `port`", func(v int) error {
+//		if v < 1 || v > 65535 {
+//			return errors.New("port must be between 1 and 65535")
+//		}
+//
+//		return nil
+//	})
+//
+// The default value is not validated.
+func (f *PosixFlagSet) IntVarFunc(p *int, name string, value int, usage string, validate func(int) error) {
+	*p = value
+	f.Var(&validatedInt{value: p, validate: validate}, name, usage)
+}
+
+// validatedInt is an int [flag.Value] which validates values before updating the flag.
+type validatedInt struct {
+	value    *int
+	validate func(int) error
+}
+
+// String returns the value as a string.
+func (v *validatedInt) String() string {
+	if v == nil || v.value == nil {
+		return strconv.Itoa(0)
+	}
+
+	return strconv.Itoa(*v.value)
+}
+
+// Set parses value with [strconv.ParseInt] and validates it, updating the flag value if valid.
+func (v *validatedInt) Set(value string) error {
+	i, err := strconv.ParseInt(value, 0, strconv.IntSize)
+	if err != nil {
+		return err
+	}
+
+	if v.validate != nil {
+		if err := v.validate(int(i)); err != nil {
+			return err
+		}
+	}
+
+	*v.value = int(i)
+
+	return nil
+}
+
+// Get fulfills the [flag.Getter] interface, allowing typed access to the flag value. In this case, returns an int.
+func (v *validatedInt) Get() any {
+	return *v.value
+}
+
+// Validate fulfills the [Validator] interface, validating the current value.
+func (v *validatedInt) Validate() error {
+	if v.validate == nil {
+		return nil
+	}
+
+	return v.validate(*v.value)
+}
@@ -0,0 +1,177 @@
+package getopt
+
+import (
+	"errors"
+	"flag"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestIntVarFunc(t *testing.T) {
+	errPortRange := errors.New("port must be between 1 and 65535")
+
+	validate := func(v int) error {
+		if v < 1 || v > 65535 {
+			return errPortRange
+		}
+
+		return nil
+	}
+
+	t.Run("should accept valid values", func(t *testing.T) {
+		var port int
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.IntVarFunc(&port, "port", 8080, "listen `port`", validate)
+
+		if port != 8080 {
+			t.Fatalf("port var not updated with expected default value: %d", port)
+		}
+
+		if err := fs.Parse([]string{"--port", "0x1BB"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if port != 443 {
+			t.Fatalf("port var not updated with expected value: %d", port)
+		}
+	})
+
+	t.Run("should reject values failing validation", func(t *testing.T) {
+		var port int
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.IntVarFunc(&port, "port", 8080, "listen `port`", validate)
+		Alias(fs.FlagSet, "port", "p")
+
+		err := fs.Parse([]string{"-p", "70000"})
+		if !errors.Is(err, errPortRange) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err.Error() != "invalid value '70000' for flag '-p': port must be between 1 and 65535" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if port != 8080 {
+			t.Fatalf("port var unexpectedly updated: %d", port)
+		}
+	})
+
+	t.Run("should reject malformed values", func(t *testing.T) {
+		var port int
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Usage = func() {}
+		fs.IntVarFunc(&port, "port", 8080, "listen `port`", validate)
+
+		if err := fs.Parse([]string{"--port=http"}); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+}
+
+func TestValidateCurrent(t *testing.T) {
+	t.Run("should return nil for valid and default values", func(t *testing.T) {
+		var (
+			port   int
+			format string
+			count  int
+			bind   net.IP
+			tags   []string
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.IntVarFunc(&port, "port", 8080, "listen port", func(v int) error {
+			if v < 1 || v > 65535 {
+				return errors.New("port must be between 1 and 65535")
+			}
+
+			return nil
+		})
+		fs.EnumVar(&format, "format", []string{"json", "text"}, "text", "output format")
+		fs.CountVar(&count, "v", "verbosity")
+		fs.IPVar(&bind, "bind", nil, "bind address")
+		fs.Var((*StringsVar)(&tags), "tag", "tags")
+
+		port, format, count, bind, tags = 443, "json", 3, net.IPv6loopback, []string{"a", "b"}
+
+		if err := fs.ValidateCurrent(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count != 3 || len(tags) != 2 {
+			t.Fatalf("flag values modified: %d %v", count, tags)
+		}
+	})
+
+	t.Run("should return error for invalid values", func(t *testing.T) {
+		var (
+			port   int
+			format string
+			bind   net.IP
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.IntVarFunc(&port, "port", 8080, "listen port", func(v int) error {
+			if v < 1 || v > 65535 {
+				return errors.New("port must be between 1 and 65535")
+			}
+
+			return nil
+		})
+		Alias(fs.FlagSet, "port", "p")
+		fs.EnumVar(&format, "format", []string{"json", "text"}, "text", "output format")
+		fs.IPVar(&bind, "bind", nil, "bind address")
+
+		// assigned directly, bypassing Parse
+		port, format, bind = 70000, "yaml", net.IP{10, 0, 0}
+
+		expected := "invalid value '?0a0000' for flag '--bind': invalid IP address '?0a0000'\n" +
+			"invalid value 'yaml' for flag '--format': getopt: value must be one of: json, text\n" +
+			"invalid value '70000' for flag '-p': port must be between 1 and 65535"
+
+		if err := fs.ValidateCurrent(); err == nil || err.Error() != expected {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if port != 70000 {
+			t.Fatalf("flag value modified: %d", port)
+		}
+	})
+	t.Run("should check values of configured types", func(t *testing.T) {
+		var (
+			since    time.Time
+			interval time.Duration
+		)
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.Var(TimeLayout(&since, time.DateOnly), "since", "show items since")
+		fs.UnitDurationVar(&interval, "interval", time.Millisecond, 0, "poll interval")
+
+		since, interval = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), 500*time.Millisecond
+
+		if err := fs.ValidateCurrent(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !since.Equal(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)) || interval != 500*time.Millisecond {
+			t.Fatalf("flag values modified: %v %v", since, interval)
+		}
+	})
+
+	t.Run("should return error for invalid default values", func(t *testing.T) {
+		var port int
+
+		fs := NewPosixFlagSet("test", flag.ContinueOnError)
+		fs.IntVarFunc(&port, "port", 0, "listen port", func(v int) error {
+			if v < 1 || v > 65535 {
+				return errors.New("port must be between 1 and 65535")
+			}
+
+			return nil
+		})
+
+		expected := "invalid value '0' for flag '--port': port must be between 1 and 65535"
+
+		if err := fs.ValidateCurrent(); err == nil || err.Error() != expected {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
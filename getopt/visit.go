@@ -0,0 +1,45 @@
+package getopt
+
+import (
+	"flag"
+	"slices"
+)
+
+// VisitFunc visits the flags of the flag set in lexicographical order, calling fn for each flag for which pred returns
+// true. Unlike [flag.FlagSet.Visit], all flags are considered, not only those which were set.
+//
+//	// visit flags changed at the command line (or through aliases)
+//	fs.VisitFunc(func(flg *flag.Flag) bool {
+//		return fs.Changed(flg.Name)
+//	}, func(flg *flag.Flag) {
+//		fmt.Printf("%s=%s\n", flg.Name, flg.Value)
+//	})
+func (f *PosixFlagSet) VisitFunc(pred func(*flag.Flag) bool, fn func(*flag.Flag)) {
+	f.VisitAll(func(flg *flag.Flag) {
+		if pred(flg) {
+			fn(flg)
+		}
+	})
+}
+
+// VisitUnset visits the flags of the flag set in lexicographical order, calling fn for each flag which was not set at
+// the command line, either directly or through any of its aliases (see [Alias]). This is the complement of
+// [flag.FlagSet.Visit], and is useful for applying defaults from other configuration sources:
+//
+//	fs.VisitUnset(func(flg *flag.Flag) {
+//		if value, ok := cfg[flg.Name]; ok {
+//			flg.Value.Set(value)
+//		}
+//	})
+func (f *PosixFlagSet) VisitUnset(fn func(*flag.Flag)) {
+	var set []flag.Value
+	f.Visit(func(flg *flag.Flag) {
+		set = append(set, flg.Value)
+	})
+
+	f.VisitFunc(func(flg *flag.Flag) bool {
+		return !slices.ContainsFunc(set, func(v flag.Value) bool {
+			return areSame(flg.Value, v)
+		})
+	}, fn)
+}
@@ -0,0 +1,54 @@
+package getopt
+
+import (
+	"flag"
+	"slices"
+	"testing"
+)
+
+func TestVisitFunc(t *testing.T) {
+	fs := NewPosixFlagSet("test", flag.ContinueOnError)
+	fs.Int("count", 12, "count")
+	fs.String("name", "default", "name")
+	fs.Bool("verbose", false, "verbose")
+	Alias(fs.FlagSet, "count", "c")
+
+	if err := fs.Parse([]string{"-c", "3", "--verbose"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var visited []string
+
+	fs.VisitFunc(func(flg *flag.Flag) bool {
+		return fs.Changed(flg.Name)
+	}, func(flg *flag.Flag) {
+		visited = append(visited, flg.Name)
+	})
+
+	if !slices.Equal([]string{"c", "count", "verbose"}, visited) {
+		t.Fatalf("unexpected flags visited: %v", visited)
+	}
+}
+
+func TestVisitUnset(t *testing.T) {
+	fs := NewPosixFlagSet("test", flag.ContinueOnError)
+	fs.Int("count", 12, "count")
+	fs.String("name", "default", "name")
+	fs.Bool("verbose", false, "verbose")
+	fs.Bool("all", false, "all")
+	Alias(fs.FlagSet, "count", "c")
+
+	if err := fs.Parse([]string{"-c", "3", "--verbose"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var visited []string
+
+	fs.VisitUnset(func(flg *flag.Flag) {
+		visited = append(visited, flg.Name)
+	})
+
+	if !slices.Equal([]string{"all", "name"}, visited) {
+		t.Fatalf("unexpected flags visited: %v", visited)
+	}
+}
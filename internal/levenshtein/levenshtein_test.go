@@ -0,0 +1,26 @@
+package levenshtein
+
+import (
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"remote", "remote", 0},
+		{"remtoe", "remote", 2},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+	}
+
+	for _, c := range cases {
+		if d := Distance(c.a, c.b); d != c.expected {
+			t.Fatalf("unexpected distance between '%s' and '%s': %d", c.a, c.b, d)
+		}
+	}
+}
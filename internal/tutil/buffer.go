@@ -0,0 +1,37 @@
+package tutil
+
+import (
+	"bytes"
+	"sync"
+)
+
+// Buffer is a goroutine-safe [bytes.Buffer]. Buffer is useful for capturing output of commands under test which write
+// from multiple goroutines.
+type Buffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write appends the contents of p to the buffer.
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+// String returns the contents of the buffer as a string.
+func (b *Buffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// Len returns the number of bytes written to the buffer.
+func (b *Buffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Len()
+}
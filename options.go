@@ -1,15 +1,47 @@
 package cmder
 
-import "io"
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"time"
+)
 
 // ExecuteOptions configure the behavior of [Execute].
 type ExecuteOptions struct {
-	args          []string
-	nativeFlags   bool
-	relaxedFlags  bool
-	bindEnv       bool
-	bindEnvPrefix string
-	interspersed  bool
+	args              []string
+	argsFunc          func() ([]string, error)
+	argsReader        io.Reader
+	nativeFlags       bool
+	relaxedFlags      bool
+	bindEnv           bool
+	bindEnvPrefix     string
+	bindEnvNameFunc   func([]string) string
+	configPath        string
+	configLoader      func([]byte, *flag.FlagSet) error
+	configOptional    bool
+	configData        []byte
+	interspersed      bool
+	usageOnEmpty      bool
+	dispatch          map[string]Command
+	completion        bool
+	versionCommand    bool
+	version           string
+	versionBuild      BuildInfo
+	helpCommand       bool
+	suggestions       bool
+	noHelpFlags       bool
+	flagErrorHandling flag.ErrorHandling
+	preRun            func(context.Context, []string) error
+	postRun           func(context.Context, []string) error
+	alwaysDestroy     bool
+	recoverPanics     bool
+	slowThreshold     time.Duration
+	slowWriter        io.Writer
+	clock             func() time.Time
+	maxDepth          int
+	errorMapper       func(error) error
 
 	usageTemplate string
 	helpTemplate  string
@@ -19,11 +51,60 @@ type ExecuteOptions struct {
 // ExecuteOption is a single option passed to [Execute].
 type ExecuteOption func(*ExecuteOptions)
 
+// newExecuteOptions builds the default [ExecuteOptions] and applies the given options.
+func newExecuteOptions(op ...ExecuteOption) *ExecuteOptions {
+	ops := &ExecuteOptions{
+		args:          os.Args[1:],
+		usageTemplate: DefaultUsageTemplate,
+		helpTemplate:  DefaultHelpTemplate,
+		outputWriter:  os.Stdout,
+		clock:         time.Now,
+	}
+
+	for _, f := range op {
+		f(ops)
+	}
+
+	return ops
+}
+
 // WithArgs configures [Execute] to run with the arguments given. By default, [Execute] will execute with arguments from
 // [os.Args].
 func WithArgs(args []string) ExecuteOption {
 	return func(ops *ExecuteOptions) {
 		ops.args = args
+		ops.argsFunc = nil
+	}
+}
+
+// WithArgsFunc is like [WithArgs], but the arguments are computed by fn when [Execute] is invoked. This is useful for
+// applications deriving arguments at execution time (e.g. from a configuration file).
+//
+//	err := cmder.Execute(ctx, cmd, cmder.WithArgsFunc(func() ([]string, error) {
+//		return loadArgs("config.toml")
+//	}))
+//
+// If fn returns an error, no command is executed and Execute returns an error wrapping [ErrIllegalExecuteOptions] and
+// the error returned by fn.
+func WithArgsFunc(fn func() ([]string, error)) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.argsFunc = fn
+	}
+}
+
+// WithArgsReader configures [Execute] to read additional arguments from r, which is useful for tools invoked with
+// argument lists exceeding operating system limits. Arguments are read in full before executing the command and are
+// appended to the arguments given by [WithArgs] (or [os.Args]).
+//
+// Arguments in r are separated by whitespace (including newlines) and may be quoted (see [getopt.SplitArgs]).
+//
+//	f, _ := os.Open("args.txt")
+//	err := cmder.Execute(ctx, cmd, cmder.WithArgsReader(f))
+//
+// If r cannot be read or contains malformed arguments, Execute returns an error.
+func WithArgsReader(r io.Reader) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.argsReader = r
 	}
 }
 
@@ -53,16 +134,73 @@ func WithRelaxedFlagParsing() ExecuteOption {
 //	COMMAND_SUBCOMMAND_SUBCOMMAND_FLAGNAME
 //
 // Command and flag names are made uppercase. Special characters are removed. Flags explicitly set at the command line
-// take precedence over environment variables.
+// take precedence over environment variables. Values of a [getopt.ResetStringsVar] given at the command line replace
+// the values bound from the environment.
 //
 //	git log --format=oneline   ->   GIT_LOG_FORMAT=oneline
 //	git log --no-abbrev-commit ->   GIT_LOG_NOABBREVCOMMIT=true
 //
+// Boolean flags accept any value parseable by [strconv.ParseBool], as well as 'yes'/'no', 'on'/'off' and 'y'/'n'
+// (case-insensitive):
+//
+//	GIT_LOG_NOABBREVCOMMIT=yes
+//
+// If a variable holds a value which the bound flag rejects, Execute returns an error wrapping
+// [ErrEnvironmentBindFailure] before any lifecycle routine is run:
+//
+//	cmder: invalid value "banana" for environment variable GIT_LOG_MAXCOUNT bound to flag --max-count: ...
+//
+// Commands may bind their flags in their own namespace by implementing [EnvPrefixCommand].
+//
+// When environment binding is enabled, the name of the variable bound to each flag is included in rendered usage and
+// help texts.
+//
+// The mapping of flags to variable names can be adjusted with [EnvBindingOption] options (e.g. to match an existing
+// naming scheme):
+//
+//	cmder.WithEnvironmentBinding(cmder.EnvPrefix("MYAPP_"), cmder.EnvNameFunc(func(path []string) string {
+//		return strings.ToUpper(strings.Join(path[1:], "__"))
+//	}))
+//
+//	git log --format=oneline   ->   MYAPP_LOG__FORMAT=oneline
+//
 // See also [WithPrefixedEnvironmentBinding].
-func WithEnvironmentBinding() ExecuteOption {
+func WithEnvironmentBinding(opts ...EnvBindingOption) ExecuteOption {
 	return func(ops *ExecuteOptions) {
 		ops.bindEnv = true
 		ops.bindEnvPrefix = ""
+		ops.bindEnvNameFunc = nil
+
+		for _, opt := range opts {
+			opt(ops)
+		}
+	}
+}
+
+// EnvBindingOption is an option given to [WithEnvironmentBinding] to adjust how flags map to environment variables.
+type EnvBindingOption func(*ExecuteOptions)
+
+// EnvPrefix configures a prefix for the names of environment variables bound to flags. The prefix is prepended as-is.
+//
+//	cmder.WithEnvironmentBinding(cmder.EnvPrefix("MYAPP_"))
+//
+//	git log --format=oneline   ->   MYAPP_GIT_LOG_FORMAT=oneline
+//
+// See also [WithPrefixedEnvironmentBinding].
+func EnvPrefix(prefix string) EnvBindingOption {
+	return func(ops *ExecuteOptions) {
+		ops.bindEnvPrefix = prefix
+	}
+}
+
+// EnvNameFunc configures the function used to derive the name of the environment variable bound to a flag. fn is given
+// the sequence of command names from the root command to the command defining the flag, followed by the flag name
+// (e.g. ["git", "log", "format"]). Any prefix (see [EnvPrefix]) is prepended to the name returned by fn.
+//
+// By default, names are made uppercase, special characters are removed and names are joined with underscores.
+func EnvNameFunc(fn func(path []string) string) EnvBindingOption {
+	return func(ops *ExecuteOptions) {
+		ops.bindEnvNameFunc = fn
 	}
 }
 
@@ -77,6 +215,57 @@ func WithPrefixedEnvironmentBinding(prefix string) ExecuteOption {
 	return func(ops *ExecuteOptions) {
 		ops.bindEnv = true
 		ops.bindEnvPrefix = prefix
+		ops.bindEnvNameFunc = nil
+	}
+}
+
+// WithConfigFile configures [Execute] to read the configuration file at path and give its contents to loader, which
+// updates flag values with [flag.FlagSet.Set]. The loader is invoked once for every command in the call stack (from the
+// root command to the leaf command) with a flag set holding the flags of that command:
+//
+//	err := cmder.Execute(ctx, cmd, cmder.WithConfigFile("/etc/app.json", func(data []byte, fs *flag.FlagSet) error {
+//		var cfg map[string]string
+//		if err := json.Unmarshal(data, &cfg); err != nil {
+//			return err
+//		}
+//
+//		for name, value := range cfg {
+//			if fs.Lookup(name) != nil {
+//				if err := fs.Set(name, value); err != nil {
+//					return err
+//				}
+//			}
+//		}
+//
+//		return nil
+//	}))
+//
+// Configuration file values have the lowest precedence: they are overridden by environment variables (see
+// [WithEnvironmentBinding]) and flags given at the command line. Flags updated by the loader are not considered set
+// (e.g. they are not visited by [flag.FlagSet.Visit] or reported by [getopt.PosixFlagSet.Changed]).
+//
+// If the file cannot be read (for instance if it doesn't exist, unless [IgnoreMissingConfigFile] is given) or the
+// loader returns an error, no command is executed and Execute returns an error wrapping [ErrConfigFileBindFailure].
+func WithConfigFile(path string, loader func([]byte, *flag.FlagSet) error, opts ...ConfigFileOption) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.configPath = path
+		ops.configLoader = loader
+		ops.configOptional = false
+
+		for _, opt := range opts {
+			opt(ops)
+		}
+	}
+}
+
+// ConfigFileOption is an option given to [WithConfigFile] to adjust how the configuration file is loaded.
+type ConfigFileOption func(*ExecuteOptions)
+
+// IgnoreMissingConfigFile configures [WithConfigFile] to ignore a configuration file which doesn't exist. Commands are
+// executed as if [WithConfigFile] wasn't given.
+func IgnoreMissingConfigFile() ConfigFileOption {
+	return func(ops *ExecuteOptions) {
+		ops.configOptional = true
 	}
 }
 
@@ -94,6 +283,213 @@ func WithInterspersedArgs() ExecuteOption {
 	}
 }
 
+// WithUsageOnEmptyArgs instructs [Execute] to render usage and return [ErrShowUsage] when a [RootCommand] with one or
+// more subcommands is invoked without any arguments, instead of invoking the Run() routine of the command.
+//
+//	git          ->   render usage for 'git'
+//	git remote   ->   render usage for 'git remote'
+//	git log      ->   run 'git log' (leaf command)
+func WithUsageOnEmptyArgs() ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.usageOnEmpty = true
+	}
+}
+
+// WithPositionalDispatch configures [Execute] to dispatch on the first positional argument given to the root command.
+// If the first argument is a key in m, the corresponding command is executed as if it were a subcommand of the root
+// command. Otherwise, the root command is executed.
+//
+// This is a lightweight alternative to [RootCommand] for applications not modeling a full command tree. Subcommands of
+// the root command take precedence over m.
+func WithPositionalDispatch(m map[string]Command) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.dispatch = m
+	}
+}
+
+// WithCompletionCommand configures [Execute] to inject a hidden 'completion' subcommand into the root command. The
+// completion subcommand writes a shell completion script for the command tree to the output writer (see
+// [WithOutputWriter]):
+//
+//	mytool completion bash
+//	mytool completion zsh
+//	mytool completion fish
+//
+// Completion scripts complete subcommand names and flags at every level of the command tree, omitting hidden
+// commands (see [GenBashCompletion]). Flags are completed as initialized with the other options given to [Execute]
+// (e.g. [WithNoHelpFlags]). If the root command already has a 'completion' subcommand, it takes precedence.
+func WithCompletionCommand() ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.completion = true
+	}
+}
+
+// WithVersionCommand configures [Execute] to inject a 'version' subcommand into the root command. The version
+// subcommand writes the given version to the output writer (see [WithOutputWriter]), followed by build information if
+// given (see [VersionBuild]):
+//
+//	$ mytool version
+//	1.2.0
+//	commit: 3f2c9e1
+//	date: 2024-05-01
+//
+// This complements the '--version' flag of commands implementing [VersionedCommand]. If the root command already has a
+// 'version' subcommand (or alias), it takes precedence.
+func WithVersionCommand(version string, opts ...VersionCommandOption) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.versionCommand = true
+		ops.version = version
+		ops.versionBuild = BuildInfo{}
+
+		for _, opt := range opts {
+			opt(ops)
+		}
+	}
+}
+
+// WithHelpCommand configures [Execute] to recognize 'help' as the first argument of the root command. The remaining
+// arguments are resolved as a subcommand path, and usage for the resolved command is rendered as if it were invoked
+// with '-h' (returning [ErrShowUsage]):
+//
+//	git help              ->   git -h
+//	git help remote       ->   git remote -h
+//	git help remote add   ->   git remote add -h
+//
+// Arguments following the last recognized subcommand name are ignored. If the root command already has a 'help'
+// subcommand, it takes precedence.
+func WithHelpCommand() ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.helpCommand = true
+	}
+}
+
+// WithNoHelpFlags configures [Execute] not to register the '-h' and '--help' flags for commands, for instance for
+// commands implementing their own help. Unless defined by the command, '-h' and '--help' are then treated like
+// undefined flags and Execute returns [flag.ErrHelp] without rendering anything. Commands may still render usage or
+// help by returning [ErrShowUsage] or [ErrShowHelp].
+func WithNoHelpFlags() ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.noHelpFlags = true
+	}
+}
+
+// WithPreRun configures [Execute] to invoke fn once before any lifecycle routine of the command tree (e.g. before
+// Check() or Initialize() of the root command). This is useful for cross-cutting concerns like logging, metrics or
+// authentication. fn is given the arguments of the root command remaining after flag parsing (including any subcommand
+// names).
+//
+// If fn returns an error, execution is aborted and the error is returned as if returned by Initialize() of the root
+// command. fn is not invoked when usage, help or version information is rendered.
+func WithPreRun(fn func(ctx context.Context, args []string) error) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.preRun = fn
+	}
+}
+
+// WithPostRun configures [Execute] to invoke fn once after the command tree has executed, whether or not execution
+// succeeded. fn is given the same arguments as the function given to [WithPreRun]. If fn returns an error, it is joined
+// with any error returned by the command tree. fn is not invoked when usage, help or version information is rendered.
+func WithPostRun(fn func(ctx context.Context, args []string) error) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.postRun = fn
+	}
+}
+
+// WithAlwaysDestroy configures [Execute] to invoke the Destroy() routine of every initialized command (see [Destroyer])
+// even if Run() or a lifecycle routine of a subcommand returns an error, much like deferred cleanup. This ensures that
+// resources acquired in Initialize() are released when execution fails.
+//
+// Destroy() routines are still invoked from the leaf command to the root command. The original error is preserved, and
+// errors returned by Destroy() routines are joined with it (see [errors.Join]). Destroy() is not invoked for a command
+// whose Check() or Initialize() routine fails.
+func WithAlwaysDestroy() ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.alwaysDestroy = true
+	}
+}
+
+// WithRecover configures [Execute] to recover from panics in lifecycle routines (Check(), Initialize(), Run() and
+// Destroy()), which is useful for long-running tools and servers where a faulty command shouldn't crash the process. A
+// recovered panic is treated like an error returned by the routine, wrapping [ErrCommandPanicked] and describing the
+// recovered value and the stack trace of the panic.
+//
+// By default, panics are not recovered.
+func WithRecover() ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.recoverPanics = true
+	}
+}
+
+// WithSlowCommandWarning configures [Execute] to write a warning to w when execution of the command tree takes longer
+// than threshold, which is useful for spotting slow commands in CI pipelines or on servers:
+//
+//	err := cmder.Execute(ctx, cmd, cmder.WithSlowCommandWarning(10*time.Second, os.Stderr))
+//
+// Execution is timed like the functions given to [WithPreRun] and [WithPostRun], which are included in the measured
+// duration. The warning is written after execution completes, whether or not it succeeded, and is not written when
+// usage, help or version information is rendered.
+func WithSlowCommandWarning(threshold time.Duration, w io.Writer) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.slowThreshold = threshold
+		ops.slowWriter = w
+	}
+}
+
+// WithFlagErrorHandling configures the error handling policy of the [flag.FlagSet] of each command. By default,
+// [flag.ContinueOnError] is used and [Execute] returns flag parsing errors.
+//
+// With [flag.ExitOnError] or [flag.PanicOnError], the error is written to the flagset output followed by the command
+// usage, and the program exits (with status 2, or 0 for undefined help flags) or panics. This can be useful during
+// development to get a stack trace for flag errors. Help flags defined by Execute render usage as usual.
+func WithFlagErrorHandling(handling flag.ErrorHandling) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.flagErrorHandling = handling
+	}
+}
+
+// WithSuggestions configures [Execute] to reject arguments which look like misspelled subcommand names. If the first
+// argument given to a command with subcommands doesn't name a subcommand but is within a small edit distance of one,
+// Execute returns [ErrUnknownCommand] without running any command:
+//
+//	git remot   ->   cmder: unknown command "remot", did you mean "remote"?
+//
+// Arguments which aren't similar to any subcommand name are given to the command as usual. Commands that accept
+// positional arguments alongside subcommands may still receive false positives, so use this option with care.
+func WithSuggestions() ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.suggestions = true
+	}
+}
+
+// WithMaxDepth limits the depth of the command stack resolved by [Execute] to n commands (including the root command).
+// If the arguments resolve a deeper subcommand, Execute returns [ErrIllegalCommandConfiguration] without running any
+// command. This guards dynamically-built command trees against cyclic or runaway subcommands.
+//
+// By default, the depth is unlimited. Values of n less than one are ignored.
+func WithMaxDepth(n int) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.maxDepth = n
+	}
+}
+
+// WithErrorMapper configures [Execute] to pass any non-nil error it is about to return through fn, returning the result
+// instead. This allows applications to translate errors into user-friendly messages or typed errors in one place.
+//
+//	cmder.WithErrorMapper(func(err error) error {
+//		if errors.Is(err, context.DeadlineExceeded) {
+//			return errors.New("operation timed out")
+//		}
+//		return err
+//	})
+//
+// The mapper is not invoked if Execute returns nil. Mappers should wrap the original error if callers need to inspect
+// it (e.g. with [errors.Is] and [ErrShowUsage]).
+func WithErrorMapper(fn func(error) error) ExecuteOption {
+	return func(ops *ExecuteOptions) {
+		ops.errorMapper = fn
+	}
+}
+
 // WithHelpTemplate is used to provide an alternate template for rendering command help text. The template is
 // rendered by the standard [text/template] package. This is particularly useful for applications which prefer to format
 // command help text differently than the cmder defaults.
@@ -111,7 +507,8 @@ func WithHelpTemplate(tmpl string) ExecuteOption {
 // rendered by the standard [text/template] package. This is particularly useful for applications which prefer to format
 // command usage information differently than the cmder defaults.
 //
-// By default, the [DefaultUsageTemplate] template is used.
+// By default, the [DefaultUsageTemplate] template is used. Commands implementing [TemplatedCommand] are always
+// rendered with their own template.
 //
 // See also [WithHelpTemplate] and [WithOutputWriter].
 func WithUsageTemplate(tmpl string) ExecuteOption {
@@ -123,6 +520,9 @@ func WithUsageTemplate(tmpl string) ExecuteOption {
 // WithOutputWriter is used to provide an alternate [io.Writer] to write rendered command usage/help text. By default,
 // [os.Stdout] is used.
 //
+// The writer applies to a single call to [Execute] only; there is no package-level output state. Concurrent calls to
+// Execute (e.g. in parallel tests) may each be given their own writer.
+//
 // See also [WithHelpTemplate] and [WithUsageTemplate].
 func WithOutputWriter(output io.Writer) ExecuteOption {
 	return func(ops *ExecuteOptions) {
@@ -0,0 +1,131 @@
+package cmder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// CommandRequest describes a command invocation without command-line syntax. See [ExecuteRequest].
+type CommandRequest struct {
+	// The sequence of subcommand names below the root command (e.g. ["remote", "add"]).
+	Path []string
+
+	// Flag values, keyed by flag name (without leading dashes). Flags are set on the command named by Path or, if the
+	// command has no such flag, on the nearest parent command defining the flag.
+	Flags map[string]string
+
+	// Positional arguments given to the command named by Path.
+	Args []string
+}
+
+// ExecuteRequest runs the command described by req in the command tree of root. ExecuteRequest is like [Execute], but
+// dispatches subcommands and sets flag values directly from req instead of parsing command-line arguments. This is
+// useful for tools which also accept structured command requests, for instance over a socket:
+//
+//	var req cmder.CommandRequest
+//	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+//		return err
+//	}
+//
+//	err := cmder.ExecuteRequest(ctx, root, req, cmder.WithOutputWriter(conn))
+//
+// Lifecycle routines are executed as described in [Execute]. Like with [Execute], req.Path may name the completion and
+// version commands (if enabled) and positional dispatch targets, and is limited by [WithMaxDepth]. Options affecting
+// argument parsing (e.g. [WithArgs]) are ignored.
+//
+// Returns [ErrUnknownCommand] if req.Path doesn't name a subcommand in the tree. Returns an error if a flag in
+// req.Flags is not defined or its value is invalid.
+func ExecuteRequest(ctx context.Context, root Command, req CommandRequest, op ...ExecuteOption) error {
+	ops := newExecuteOptions(op...)
+
+	err := executeRequest(ctx, root, req, ops)
+
+	// rewrite the error (if applicable)
+	if err != nil && ops.errorMapper != nil {
+		err = ops.errorMapper(err)
+	}
+
+	return err
+}
+
+// executeRequest builds the call stack for req and executes it.
+func executeRequest(ctx context.Context, cmd Command, req CommandRequest, ops *ExecuteOptions) error {
+	if cmd == nil {
+		return errors.Join(ErrIllegalCommandConfiguration, errors.New("cmder: command cannot be nil"))
+	}
+
+	// read configuration file (if applicable)
+	if err := readConfigFile(ops); err != nil {
+		return err
+	}
+
+	var (
+		cmds   []*command
+		path   []string
+		parent *command
+	)
+
+	for i := 0; cmd != nil; i++ {
+		path = append(path, cmd.Name())
+
+		this, err := initCommand(cmd, parent, path, ops)
+		if err != nil {
+			return err
+		}
+
+		this.args = append(slices.Clone(req.Path[i:]), req.Args...)
+
+		cmds = append(cmds, this)
+		parent = this
+
+		if i == len(req.Path) {
+			break
+		}
+
+		subcommands, err := dispatchSubcommands(cmd)
+		if err != nil {
+			return err
+		}
+
+		sub, ok := lookupSubcommand(cmd, subcommands, req.Path[i], i == 0, ops)
+		if !ok {
+			return fmt.Errorf("%w \"%s\" for command '%s'", ErrUnknownCommand, req.Path[i], strings.Join(path, " "))
+		}
+
+		cmd = sub
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(req.Flags)) {
+		if err := setRequestFlag(cmds, name, req.Flags[name]); err != nil {
+			return err
+		}
+	}
+
+	stack := make([]command, 0, len(cmds))
+	for _, c := range cmds {
+		stack = append(stack, *c)
+	}
+
+	return run(ctx, stack, ops)
+}
+
+// setRequestFlag sets the flag with the given name on the last command in cmds defining it.
+func setRequestFlag(cmds []*command, name, value string) error {
+	for i := len(cmds) - 1; i >= 0; i-- {
+		if cmds[i].fs.Lookup(name) == nil {
+			continue
+		}
+
+		if err := cmds[i].fs.Set(name, value); err != nil {
+			return fmt.Errorf("cmder: invalid value '%s' for flag '%s': %w", value, name, err)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("cmder: unknown flag '%s' for command '%s'", name, cmds[len(cmds)-1].Path())
+}
@@ -0,0 +1,124 @@
+package cmder
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/brandon1024/cmder/internal/tutil"
+)
+
+func TestExecuteRequest(t *testing.T) {
+	var (
+		verbose bool
+		name    string
+		args    []string
+		ran     bool
+	)
+
+	tree := func() Command {
+		return Tree(
+			New("tool").Flags(func(fs *flag.FlagSet) {
+				fs.BoolVar(&verbose, "verbose", false, "verbose output")
+			}).Sub(
+				New("remote").Sub(
+					New("add").Usage("tool remote add <url>").Flags(func(fs *flag.FlagSet) {
+						fs.StringVar(&name, "name", "origin", "remote name")
+					}).Run(func(ctx context.Context, a []string) error {
+						ran, args = true, a
+						return nil
+					}),
+				),
+			),
+		)
+	}
+
+	reset := func() {
+		verbose, name, args, ran = false, "", nil, false
+	}
+
+	t.Run("should dispatch nested command with flags and args", func(t *testing.T) {
+		reset()
+
+		err := ExecuteRequest(t.Context(), tree(), CommandRequest{
+			Path:  []string{"remote", "add"},
+			Flags: map[string]string{"name": "upstream", "verbose": "true"},
+			Args:  []string{"https://example.com/repo.git"},
+		})
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, ran))
+		tutil.Assert(t, tutil.Eq(true, verbose))
+		tutil.Assert(t, tutil.Eq("upstream", name))
+		tutil.Assert(t, tutil.Match([]string{"https://example.com/repo.git"}, args))
+	})
+
+	t.Run("should not parse args as flags", func(t *testing.T) {
+		reset()
+
+		err := ExecuteRequest(t.Context(), tree(), CommandRequest{
+			Path: []string{"remote", "add"},
+			Args: []string{"--name", "upstream"},
+		})
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq("origin", name))
+		tutil.Assert(t, tutil.Match([]string{"--name", "upstream"}, args))
+	})
+
+	t.Run("should render usage if help flag given", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		reset()
+
+		err := ExecuteRequest(t.Context(), tree(), CommandRequest{
+			Path:  []string{"remote", "add"},
+			Flags: map[string]string{"h": "true"},
+		}, WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq(false, ran))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(buf.String(), "tool remote add <url>")))
+	})
+
+	t.Run("should return error for unknown subcommand", func(t *testing.T) {
+		err := ExecuteRequest(t.Context(), tree(), CommandRequest{Path: []string{"remote", "rm"}})
+		tutil.Assert(t, tutil.IsErr(err, ErrUnknownCommand))
+		tutil.Assert(t, tutil.Eq(`cmder: unknown command "rm" for command 'tool remote'`, err.Error()))
+	})
+
+	t.Run("should return error if maximum command depth exceeded", func(t *testing.T) {
+		reset()
+
+		err := ExecuteRequest(t.Context(), tree(), CommandRequest{Path: []string{"remote", "add"}}, WithMaxDepth(2))
+		tutil.Assert(t, tutil.IsErr(err, ErrIllegalCommandConfiguration))
+		tutil.Assert(t, tutil.Eq(true, strings.Contains(err.Error(), "'tool remote add' exceeds maximum command depth")))
+		tutil.Assert(t, tutil.Eq(false, ran))
+	})
+
+	t.Run("should dispatch to injected version command", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := ExecuteRequest(t.Context(), tree(), CommandRequest{Path: []string{"version"}},
+			WithVersionCommand("v1.2.3"), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq("v1.2.3\n", buf.String()))
+	})
+
+	t.Run("should return error for unknown flag", func(t *testing.T) {
+		err := ExecuteRequest(t.Context(), tree(), CommandRequest{
+			Path:  []string{"remote"},
+			Flags: map[string]string{"name": "upstream"},
+		})
+		tutil.Assert(t, tutil.Eq("cmder: unknown flag 'name' for command 'tool remote'", err.Error()))
+	})
+
+	t.Run("should return error for invalid flag value", func(t *testing.T) {
+		err := ExecuteRequest(t.Context(), tree(), CommandRequest{Flags: map[string]string{"verbose": "maybe"}})
+		tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(err.Error(), "cmder: invalid value 'maybe' for flag 'verbose'")))
+	})
+
+	t.Run("should return error for nil command", func(t *testing.T) {
+		err := ExecuteRequest(t.Context(), nil, CommandRequest{})
+		tutil.Assert(t, tutil.IsErr(err, ErrIllegalCommandConfiguration))
+	})
+}
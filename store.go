@@ -0,0 +1,71 @@
+package cmder
+
+import (
+	"context"
+	"sync"
+)
+
+// Store is a key-value store shared by the commands of a command tree during a single invocation of [Execute]. Parent
+// commands can place values in the store (e.g. an opened configuration or an authenticated client) for their
+// subcommands to use, without resorting to global variables:
+//
+//	func (c *RootCommand) Initialize(ctx context.Context, args []string) error {
+//		client, err := dial(c.addr)
+//		if err != nil {
+//			return err
+//		}
+//
+//		cmder.StoreFrom(ctx).Set(clientKey{}, client)
+//		return nil
+//	}
+//
+//	func (c *ListCommand) Run(ctx context.Context, args []string) error {
+//		client, _ := cmder.StoreFrom(ctx).Get(clientKey{})
+//		return list(ctx, client.(*Client))
+//	}
+//
+// Like [context.WithValue], keys must be comparable and should be of an unexported type to avoid collisions. A Store is
+// safe for concurrent use.
+type Store struct {
+	mu     sync.RWMutex
+	values map[any]any
+}
+
+// storeKey is the context key of the [Store] seeded by [Execute].
+type storeKey struct{}
+
+// StoreFrom returns the [Store] shared by the commands of the command tree, or nil if ctx is not derived from a context
+// given to a lifecycle routine by [Execute].
+func StoreFrom(ctx context.Context) *Store {
+	store, _ := ctx.Value(storeKey{}).(*Store)
+	return store
+}
+
+// Set stores value under key, replacing any value previously stored under key.
+func (s *Store) Set(key, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.values == nil {
+		s.values = map[any]any{}
+	}
+
+	s.values[key] = value
+}
+
+// Get returns the value stored under key, and whether a value was found.
+func (s *Store) Get(key any) (any, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Delete removes the value stored under key, if any.
+func (s *Store) Delete(key any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+}
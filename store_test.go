@@ -0,0 +1,70 @@
+package cmder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/brandon1024/cmder/internal/tutil"
+)
+
+func TestStore(t *testing.T) {
+	type clientKey struct{}
+
+	t.Run("should share values from parent to child commands", func(t *testing.T) {
+		var (
+			value any
+			found bool
+		)
+
+		cmd := Tree(
+			New("root").Init(func(ctx context.Context, args []string) error {
+				StoreFrom(ctx).Set(clientKey{}, "client")
+				return nil
+			}).Sub(
+				New("child").Run(func(ctx context.Context, args []string) error {
+					value, found = StoreFrom(ctx).Get(clientKey{})
+					return nil
+				}),
+			),
+		)
+
+		err := Execute(t.Context(), cmd, WithArgs([]string{"child"}))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, found))
+		tutil.Assert(t, tutil.Eq[any]("client", value))
+	})
+
+	t.Run("should seed empty store for each execution", func(t *testing.T) {
+		var stores []*Store
+
+		cmd := Tree(New("root").Run(func(ctx context.Context, args []string) error {
+			_, found := StoreFrom(ctx).Get(clientKey{})
+			tutil.Assert(t, tutil.Eq(false, found))
+
+			StoreFrom(ctx).Set(clientKey{}, "client")
+			stores = append(stores, StoreFrom(ctx))
+			return nil
+		}))
+
+		for range 2 {
+			tutil.Assert(t, tutil.NilErr(Execute(t.Context(), cmd, WithArgs(nil))))
+		}
+
+		tutil.Assert(t, tutil.Eq(2, len(stores)))
+		tutil.Assert(t, tutil.Eq(false, stores[0] == stores[1]))
+	})
+
+	t.Run("should return nil outside of execution", func(t *testing.T) {
+		tutil.Assert(t, tutil.Eq(true, StoreFrom(t.Context()) == nil))
+	})
+
+	t.Run("should delete values", func(t *testing.T) {
+		var store Store
+
+		store.Set(clientKey{}, "client")
+		store.Delete(clientKey{})
+
+		_, found := store.Get(clientKey{})
+		tutil.Assert(t, tutil.Eq(false, found))
+	})
+}
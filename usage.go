@@ -3,7 +3,10 @@ package cmder
 import (
 	"bytes"
 	"errors"
+	"flag"
+	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -13,12 +16,32 @@ import (
 // DefaultHelpTemplate is a text template for rendering extended command help information.
 const DefaultHelpTemplate = `{{ trim .Command.HelpText }}{{ println }}{{ println }}` + DefaultUsageTemplate
 
-// DefaultUsageTemplate is a text template for rendering command usage information.
+// DefaultUsageTemplate is a text template for rendering command usage information. If the command has no usage line
+// (see [Documented]), the full command path is rendered instead (e.g. 'git remote add [flags]'). Aliases of the command
+// (see [AliasedCommand]) are listed below the usage line. Flags inherited from parent commands are listed separately
+// under 'Global Flags', as done by Cobra.
+//
+// Commands which merely dispatch to subcommands (commands with subcommands but no flags other than the help flags) are
+// rendered in a compact layout focused on the available subcommands, omitting the 'Flags' section.
 const DefaultUsageTemplate = `Usage:
 {{- println -}}
-{{- printf "  %s" (trim .Command.UsageLine) -}}
+{{- with (trim .Command.UsageLine) -}}
+	{{- printf "  %s" . -}}
+{{- else -}}
+	{{- if (dispatcher .) -}}
+		{{- printf "  %s [command]" .Path -}}
+	{{- else -}}
+		{{- printf "  %s [flags]" .Path -}}
+	{{- end -}}
+{{- end -}}
 {{- println -}}
 
+{{- with (aliases .) -}}
+	{{- println -}}
+	{{- println "Aliases:" -}}
+	{{- printf "  %s\n" (join . ", ") -}}
+{{- end -}}
+
 {{- with .Command.ExampleText -}}
 	{{- println -}}
 	{{- println "Examples:" -}}
@@ -36,9 +59,18 @@ const DefaultUsageTemplate = `Usage:
 	{{- end -}}
 {{- end -}}
 
-{{- with (flags .) -}}
+{{- if not (dispatcher .) -}}
+	{{- with (local_flags .) -}}
+		{{- println -}}
+		{{- println "Flags:" -}}
+
+		{{- print (flag_usage .) -}}
+	{{- end -}}
+{{- end -}}
+
+{{- with (global_flags .) -}}
 	{{- println -}}
-	{{- println "Flags:" -}}
+	{{- println "Global Flags:" -}}
 
 	{{- print (flag_usage .) -}}
 {{- end -}}
@@ -48,15 +80,129 @@ const DefaultUsageTemplate = `Usage:
 	{{- printf "Use \"%s [command] --help\" for more information about a command.\n" .Command.Name -}}
 {{- end -}}`
 
+// StdFlagHelpTemplate is a terse text template for rendering command help information in the style of the standard
+// [flag] package: a usage line followed by a description of each flag (see [flag.PrintDefaults]). Subcommands, help
+// texts and examples are not rendered.
+//
+//	cmder.Execute(ctx, cmd, cmder.WithHelpTemplate(cmder.StdFlagHelpTemplate))
+const StdFlagHelpTemplate = `
+{{- with (trim .Command.UsageLine) -}}
+	{{- printf "Usage: %s\n" . -}}
+{{- else -}}
+	{{- printf "Usage of %s:\n" .Path -}}
+{{- end -}}
+
+{{- print (flag_usage (flags .)) -}}`
+
+// LongHelpTemplate is a text template for rendering extended command help information in the style of kubectl: the
+// command description, examples, subcommands and flags, followed by the usage synopsis. Flag descriptions are rendered
+// in full on their own indented lines, wrapped at 80 columns, which is useful for commands with long flag
+// descriptions.
+//
+//	cmder.Execute(ctx, cmd, cmder.WithHelpTemplate(cmder.LongHelpTemplate))
+const LongHelpTemplate = `
+{{- with (trim .Command.HelpText) -}}
+	{{- println . -}}
+	{{- println -}}
+{{- end -}}
+
+{{- with .Command.ExampleText -}}
+	{{- println "Examples:" -}}
+	{{- range (lines (trim .)) -}}
+		{{- printf "  %s" . -}}
+	{{- end -}}
+	{{- println -}}
+	{{- println -}}
+{{- end -}}
+
+{{- with (commands .) -}}
+	{{- println "Available Commands:" -}}
+	{{- range . -}}
+		{{- printf "  %-13s  %s\n" .Name .ShortHelpText -}}
+	{{- end -}}
+	{{- println -}}
+{{- end -}}
+
+{{- with (flag_usage (usage_width 80 (flags .))) -}}
+	{{- println "Options:" -}}
+	{{- print . -}}
+	{{- println -}}
+{{- end -}}
+
+{{- println "Usage:" -}}
+{{- with (trim .Command.UsageLine) -}}
+	{{- printf "  %s" . -}}
+{{- else -}}
+	{{- printf "  %s [options]" .Path -}}
+{{- end -}}
+{{- println -}}
+
+{{- if (commands .) -}}
+	{{- println -}}
+	{{- printf "Use \"%s [command] --help\" for more information about a command.\n" .Command.Name -}}
+{{- end -}}`
+
 // ErrShowUsage instructs cmder to render usage.
 var ErrShowUsage = errors.New("cmder: usage requested")
 
 // ErrShowHelp instructs cmder to render help.
 var ErrShowHelp = errors.New("cmder: help requested")
 
-// usage renders usage text for a [Command].
+// ErrShowVersion instructs cmder to render the version of a [VersionedCommand].
+var ErrShowVersion = errors.New("cmder: version requested")
+
+// RenderUsage renders usage text for the command at the given path in the command tree of cmd to w, without executing
+// any command lifecycle routines. The path is the sequence of subcommand names below cmd. An empty path renders usage
+// for cmd itself.
+//
+//	err := cmder.RenderUsage(cmd, []string{"remote", "add"}, os.Stdout)
+//
+// Flags of the command are initialized (see [FlagInitializer]) so that they can be rendered. Options that affect usage
+// rendering (e.g. [WithUsageTemplate] and [WithNativeFlags]) may be given. Any [WithOutputWriter] option is ignored in
+// favour of w.
+//
+// Returns [ErrIllegalCommandConfiguration] if cmd is nil, or an error if path doesn't name a subcommand in the tree.
+func RenderUsage(cmd Command, path []string, w io.Writer, op ...ExecuteOption) error {
+	if cmd == nil {
+		return errors.Join(ErrIllegalCommandConfiguration, errors.New("cmder: command cannot be nil"))
+	}
+
+	ops := newExecuteOptions(op...)
+	ops.outputWriter = w
+
+	this, err := newCommand(cmd, nil, []string{cmd.Name()}, ops)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range path {
+		subcommands, err := dispatchSubcommands(this.Command)
+		if err != nil {
+			return err
+		}
+
+		sub, ok := subcommands[name]
+		if !ok {
+			return fmt.Errorf("cmder: command '%s' has no subcommand '%s'", this.Path(), name)
+		}
+
+		if this, err = newCommand(sub, this, append(slices.Clone(this.path), sub.Name()), ops); err != nil {
+			return err
+		}
+	}
+
+	return usage(*this, ops)
+}
+
+// usage renders usage text for a [Command]. Commands implementing [TemplatedCommand] are rendered with their own
+// template.
 func usage(cmd command, ops *ExecuteOptions) error {
-	tmpl, err := template.New("usage").Funcs(funcs(ops)).Parse(ops.usageTemplate)
+	text := ops.usageTemplate
+	if c, ok := cmd.Command.(TemplatedCommand); ok {
+		text = c.UsageTemplate()
+	}
+
+	tmpl, err := template.New("usage").Funcs(funcs(ops, false)).Parse(text)
 	if err != nil {
 		return err
 	}
@@ -66,7 +212,7 @@ func usage(cmd command, ops *ExecuteOptions) error {
 
 // help renders extended help text for a [Command].
 func help(cmd command, ops *ExecuteOptions) error {
-	tmpl, err := template.New("help").Funcs(funcs(ops)).Parse(ops.helpTemplate)
+	tmpl, err := template.New("help").Funcs(funcs(ops, true)).Parse(ops.helpTemplate)
 	if err != nil {
 		return err
 	}
@@ -74,13 +220,30 @@ func help(cmd command, ops *ExecuteOptions) error {
 	return tmpl.Execute(ops.outputWriter, cmd)
 }
 
-// funcs returns template functions which can be used in usage/help text templates.
+// version renders the version of a [VersionedCommand].
+func version(cmd command, ops *ExecuteOptions) error {
+	v, ok := cmd.Command.(VersionedCommand)
+	if !ok {
+		return nil
+	}
+
+	_, err := fmt.Fprintln(ops.outputWriter, v.Version())
+	return err
+}
+
+// funcs returns template functions which can be used in usage/help text templates. When rendering help text (long is
+// true), flags render their long descriptions (see [getopt.LongUsageVar]) where available.
 //
 // The following template functions are available:
 //
 //   - commands(c):            Collect all subcommands of c into a map, keyed by name.
+//   - aliases(c):             Return the name of c followed by its aliases, or nil if c has no aliases.
+//   - dispatcher(c):          Check if c has subcommands but no flags other than those registered by [Execute].
 //   - flags(c):               Return the flagset of c.
+//   - local_flags(c):         Return the flagset of c without flags inherited from parent commands, or nil if empty.
+//   - global_flags(c):        Return the flags c inherited from parent commands, or nil if none.
 //   - flag_usage(fs):         Return the rendered flag usage for the given flagset.
+//   - env(c, name):           Return the environment variable bound to flag name of c (see [WithEnvironmentBinding]).
 //   - lower(str):             Return string argument in lowercase.
 //   - upper(str):             Return string argument in uppercase.
 //   - split(str):             Split a string.
@@ -89,19 +252,27 @@ func help(cmd command, ops *ExecuteOptions) error {
 //   - contains(str, other):   Check if a string contains another string
 //   - trim(str):              Trim all leading and trailing whitespace of str.
 //   - lines(str):             Split str into a slice of text lines.
-func funcs(ops *ExecuteOptions) template.FuncMap {
+//   - usage_width(width, fs): Return fs, wrapping its flag usage text at width columns (see [getopt.PosixFlagSet]
+//     UsageWidth). Native flagsets (see [WithNativeFlags]) are returned as-is.
+func funcs(ops *ExecuteOptions, long bool) template.FuncMap {
 	return template.FuncMap{
-		"commands":   subcommands,
-		"flags":      flags(ops),
-		"flag_usage": flagUsage,
-		"lower":      strings.ToLower,
-		"upper":      strings.ToUpper,
-		"split":      strings.Split,
-		"replace":    strings.ReplaceAll,
-		"join":       strings.Join,
-		"contains":   strings.Contains,
-		"trim":       strings.TrimSpace,
-		"lines":      strings.Lines,
+		"commands":     subcommands,
+		"aliases":      aliases,
+		"dispatcher":   dispatcher,
+		"flags":        flags(ops, long),
+		"local_flags":  inheritedFlags(ops, long, false),
+		"global_flags": inheritedFlags(ops, long, true),
+		"flag_usage":   flagUsage,
+		"env":          env(ops),
+		"lower":        strings.ToLower,
+		"upper":        strings.ToUpper,
+		"split":        strings.Split,
+		"replace":      strings.ReplaceAll,
+		"join":         strings.Join,
+		"contains":     strings.Contains,
+		"trim":         strings.TrimSpace,
+		"lines":        strings.Lines,
+		"usage_width":  usageWidth,
 	}
 }
 
@@ -118,18 +289,122 @@ func subcommands(cmd command) map[string]Command {
 	return subcommands
 }
 
+// aliases returns the name of cmd followed by its aliases (see [AliasedCommand]), or nil if cmd has no aliases.
+func aliases(cmd command) []string {
+	c, ok := cmd.Command.(AliasedCommand)
+	if !ok || len(c.Aliases()) == 0 {
+		return nil
+	}
+
+	return append([]string{cmd.Name()}, c.Aliases()...)
+}
+
+// dispatcher checks if cmd merely dispatches to subcommands: cmd has visible subcommands, and no visible flags other
+// than those registered by [Execute] (e.g. help flags) or inherited from parent commands.
+func dispatcher(cmd command) bool {
+	if len(subcommands(cmd)) == 0 {
+		return false
+	}
+
+	var own bool
+	cmd.fs.VisitAll(func(flg *flag.Flag) {
+		if _, inherited := cmd.inherited[flg.Name]; !getopt.IsHidden(flg) && !inherited &&
+			!slices.Contains(cmd.builtin, flg.Name) {
+			own = true
+		}
+	})
+
+	return !own
+}
+
 // flags returns a template func which produces a flagset (either a standard [flag.FlagSet] or [getopt.PosixFlagSet])
-// according to the options defines in ops.
-func flags(ops *ExecuteOptions) func(cmd command) any {
+// according to the options defines in ops. If long is true, the [getopt.PosixFlagSet] renders long flag descriptions.
+//
+// Hidden flags (see [getopt.Hide]) are omitted from the resulting flagset, regardless of how it is rendered.
+func flags(ops *ExecuteOptions, long bool) func(cmd command) any {
+	return func(cmd command) any {
+		return flagsetFor(visibleFlags(cmd, ops), ops, long)
+	}
+}
+
+// inheritedFlags returns a template func which produces a flagset like [flags], restricted to the flags inherited from
+// parent commands (if inherited is true) or to the flags of the command itself (otherwise). The template func returns
+// nil if no such flag is visible.
+func inheritedFlags(ops *ExecuteOptions, long, inherited bool) func(cmd command) any {
 	return func(cmd command) any {
-		if ops.nativeFlags {
-			return cmd.fs
+		fs := flag.NewFlagSet(cmd.fs.Name(), cmd.fs.ErrorHandling())
+		fs.SetOutput(cmd.fs.Output())
+
+		visibleFlags(cmd, ops).VisitAll(func(flg *flag.Flag) {
+			if _, ok := cmd.inherited[flg.Name]; ok == inherited {
+				fs.Var(flg.Value, flg.Name, flg.Usage)
+				fs.Lookup(flg.Name).DefValue = flg.DefValue
+			}
+		})
+
+		var empty = true
+		fs.VisitAll(func(*flag.Flag) {
+			empty = false
+		})
+
+		if empty {
+			return nil
+		}
+
+		return flagsetFor(fs, ops, long)
+	}
+}
+
+// flagsetFor wraps fs with a [getopt.PosixFlagSet] unless native flags are used (see [WithNativeFlags]).
+func flagsetFor(fs *flag.FlagSet, ops *ExecuteOptions, long bool) any {
+	if ops.nativeFlags {
+		return fs
+	}
+
+	return &getopt.PosixFlagSet{FlagSet: fs, RelaxedParsing: ops.relaxedFlags, PrintLongUsage: long}
+}
+
+// env returns a template func which produces the name of the environment variable bound to a flag of a command.
+// Returns an empty string if environment binding is disabled.
+func env(ops *ExecuteOptions) func(cmd command, name string) string {
+	return func(cmd command, name string) string {
+		if !ops.bindEnv {
+			return ""
 		}
 
-		return &getopt.PosixFlagSet{FlagSet: cmd.fs, RelaxedParsing: ops.relaxedFlags}
+		return envVariable(cmd, name, ops)
 	}
 }
 
+// visibleFlags returns a copy of the flagset of cmd without hidden flags. If environment binding is enabled (see
+// [WithEnvironmentBinding]), the usage of each flag is suffixed with the name of the environment variable bound to it.
+func visibleFlags(cmd command, ops *ExecuteOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet(cmd.fs.Name(), cmd.fs.ErrorHandling())
+	fs.SetOutput(cmd.fs.Output())
+
+	cmd.fs.VisitAll(func(flg *flag.Flag) {
+		if getopt.IsHidden(flg) {
+			return
+		}
+
+		// inherited flags are bound to the environment variable of the command declaring them
+		owner, ok := cmd.inherited[flg.Name]
+		if !ok {
+			owner = cmd
+		}
+
+		usage := flg.Usage
+		if ops.bindEnv {
+			usage = fmt.Sprintf("%s (env %s)", flg.Usage, envVariable(owner, flg.Name, ops))
+		}
+
+		fs.Var(flg.Value, flg.Name, usage)
+		fs.Lookup(flg.Name).DefValue = flg.DefValue
+	})
+
+	return fs
+}
+
 // flagsetPrinter is a flagset (either [flag.FlagSet] or [getopt.PosixFlagSet]) which can render its usage.
 type flagsetPrinter interface {
 	PrintDefaults()
@@ -149,3 +424,13 @@ func flagUsage(fs flagsetPrinter) string {
 
 	return buf.String()
 }
+
+// usageWidth sets the [getopt.PosixFlagSet] UsageWidth of fs to width, returning fs. Other flagsets are returned
+// unchanged.
+func usageWidth(width int, fs flagsetPrinter) flagsetPrinter {
+	if posix, ok := fs.(*getopt.PosixFlagSet); ok {
+		posix.UsageWidth = width
+	}
+
+	return fs
+}
@@ -2,8 +2,10 @@ package cmder
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
@@ -120,8 +122,6 @@ Flags:
     	render template with arguments (key=value) (default k=v)
   -hosts value
     	specify remote hosts (e.g. tcp://127.0.0.1) (default hello,world)
-  -poll-interval value
-    	attempt to poll the device status more frequently than advertised (default 0s)
   -r value
     	specify remote hosts (e.g. tcp://127.0.0.1) (default hello,world)
   -reconnect-interval duration
@@ -142,6 +142,58 @@ Flags:
 Use "test [command] --help" for more information about a command.
 `
 
+const ExpectedStdFlagHelp = `Usage: test [subcommands] [flags] [args]
+  -a <address>, --addr=<address>
+      address and port of the device (e.g. 192.168.1.1:4567)
+
+  -t <key=value>, --arg=<key=value> (default k=v)
+      render template with arguments (key=value)
+
+  -r <value>, --hosts=<value> (default hello,world)
+      specify remote hosts (e.g. tcp://127.0.0.1)
+
+  --reconnect-interval=<duration> (default 1m0s)
+      interval between connection attempts (e.g. 1m)
+
+  -s <serial>, --serial-number=<serial>
+      serial number of the device (e.g. 10293894a)
+
+  --web.disable-exporter-metrics
+      exclude metrics about the exporter itself (go_*)
+
+  --web.listen-address=<string> (default :9090)
+      address on which to expose metrics
+
+  --web.telemetry-path=<string> (default /metrics)
+      path under which to expose metrics
+`
+
+const ExpectedLongHelp = `Apply a configuration to a resource by file name or stdin.
+
+Examples:
+  # Apply the configuration in pod.json to a pod
+  kubectl apply -f ./pod.json
+
+Options:
+  --force
+      If true, immediately remove resources from API and bypass graceful
+      deletion. Note that immediate deletion of some resources may result in
+      inconsistency or data loss and requires confirmation.
+
+  --grace-period=<int> (default -1)
+      Period of time in seconds given to the resource to terminate gracefully.
+      Ignored if negative. Set to 1 for immediate shutdown.
+
+  -h
+      show command usage information
+
+  --help
+      show command help information
+
+Usage:
+  kubectl apply (-f FILENAME | -k DIRECTORY) [options]
+`
+
 func TestHelp(t *testing.T) {
 	child1 := &BaseCommand{
 		CommandName: "child-1",
@@ -197,6 +249,81 @@ func TestHelp(t *testing.T) {
 	cmd.fs.String("web.telemetry-path", "/metrics", "path under which to expose metrics")
 	cmd.fs.Bool("web.disable-exporter-metrics", false, "exclude metrics about the exporter itself (go_*)")
 
+	t.Run("StdFlagHelpTemplate", func(t *testing.T) {
+		t.Run("should render correctly", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := help(cmd, &ExecuteOptions{
+				helpTemplate: StdFlagHelpTemplate,
+				outputWriter: &buf,
+			})
+			tutil.Assert(t, tutil.NilErr(err))
+
+			t.Logf("result:\n%s", buf.String())
+
+			if diff := cmp.Diff(ExpectedStdFlagHelp, buf.String()); diff != "" {
+				t.Fatalf("usage text mismatch (-want +got):\n%s", diff)
+			}
+		})
+
+		t.Run("should render command path if usage line is empty", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := Execute(t.Context(), Tree(New("tool").Sub(New("sub"))), WithArgs([]string{"sub", "--help"}),
+				WithHelpTemplate(StdFlagHelpTemplate), WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowHelp))
+			tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(buf.String(), "Usage of tool sub:\n  -h\n")))
+		})
+	})
+
+	t.Run("LongHelpTemplate", func(t *testing.T) {
+		t.Run("should render full flag descriptions", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			apply := &BaseCommand{
+				CommandName: "apply",
+				CommandDocumentation: CommandDocumentation{
+					Usage:    "kubectl apply (-f FILENAME | -k DIRECTORY) [options]",
+					Help:     "Apply a configuration to a resource by file name or stdin.",
+					Examples: "# Apply the configuration in pod.json to a pod\nkubectl apply -f ./pod.json",
+				},
+				InitFlagsFunc: func(fs *flag.FlagSet) {
+					fs.Bool("force", false, "If true, immediately remove resources from API and bypass graceful "+
+						"deletion. Note that immediate deletion of some resources may result in inconsistency or data "+
+						"loss and requires confirmation.")
+					fs.Int("grace-period", -1, "Period of time in seconds given to the resource to terminate "+
+						"gracefully. Ignored if negative. Set to 1 for immediate shutdown.")
+				},
+			}
+
+			err := Execute(t.Context(), apply, WithArgs([]string{"--help"}), WithHelpTemplate(LongHelpTemplate),
+				WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowHelp))
+
+			t.Logf("result:\n%s", buf.String())
+
+			if diff := cmp.Diff(ExpectedLongHelp, buf.String()); diff != "" {
+				t.Fatalf("usage text mismatch (-want +got):\n%s", diff)
+			}
+		})
+
+		t.Run("should not render options header without visible flags", func(t *testing.T) {
+			var buf bytes.Buffer
+
+			version := Tree(New("version").Help("Print the client version information.").Flags(func(fs *flag.FlagSet) {
+				fs.Bool("debug", false, "debug output")
+				getopt.Hide(fs, "debug")
+			}).Run(func(context.Context, []string) error {
+				return ErrShowHelp
+			}))
+
+			err := Execute(t.Context(), version, WithArgs(nil), WithNoHelpFlags(), WithHelpTemplate(LongHelpTemplate),
+				WithOutputWriter(&buf))
+			tutil.Assert(t, tutil.IsErr(err, ErrShowHelp))
+			tutil.Assert(t, tutil.Eq("Print the client version information.\n\nUsage:\n  version [options]\n", buf.String()))
+		})
+	})
+
 	t.Run("DefaultHelpTemplate", func(t *testing.T) {
 		t.Run("should render correctly", func(t *testing.T) {
 			var buf bytes.Buffer
@@ -342,10 +469,10 @@ Flags:
   --text-zero=<value> (default INFO)
       textvar with zero default value
 
-  --time-non-zero=<value> (default 1970-01-04T00:00:00Z)
+  --time-non-zero=<time> (default 1970-01-04T00:00:00Z)
       time flag with non-zero default value
 
-  --time-zero=<value>
+  --time-zero=<time>
       time flag with zero default value
 
   --uint-non-zero=<uint> (default 14)
@@ -466,4 +593,388 @@ func TestUsage(t *testing.T) {
 			t.Fatalf("usage text mismatch (-want +got):\n%s", diff)
 		}
 	})
+
+	t.Run("should render bound environment variables", func(t *testing.T) {
+		cmd := command{
+			Command: &BaseCommand{
+				CommandName: "show",
+				CommandDocumentation: CommandDocumentation{
+					Usage: "show [flags]",
+				},
+			},
+			fs:   flag.NewFlagSet("show", flag.ContinueOnError),
+			path: []string{"mytool", "show"},
+		}
+
+		cmd.fs.String("format", "short", "output `format`")
+		cmd.fs.Bool("all", false, "show all entries")
+
+		var buf bytes.Buffer
+
+		err := usage(cmd, &ExecuteOptions{
+			usageTemplate: DefaultUsageTemplate,
+			outputWriter:  &buf,
+			bindEnv:       true,
+		})
+		tutil.Assert(t, tutil.NilErr(err))
+
+		t.Logf("result:\n%s", buf.String())
+
+		expected := `Usage:
+  show [flags]
+
+Flags:
+  --all
+      show all entries (env MYTOOL_SHOW_ALL)
+
+  --format=<format> (default short)
+      output format (env MYTOOL_SHOW_FORMAT)
+`
+
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Fatalf("usage text mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should render inherited flags separately", func(t *testing.T) {
+		cmd := command{
+			Command: &BaseCommand{
+				CommandName: "add",
+				CommandDocumentation: CommandDocumentation{
+					Usage: "add [flags] <name> <url>",
+				},
+			},
+			fs:        flag.NewFlagSet("add", flag.ContinueOnError),
+			inherited: map[string]command{"verbose": {}, "v": {}},
+		}
+
+		var verbose bool
+
+		cmd.fs.Bool("fetch", false, "fetch the remote after adding it")
+		cmd.fs.BoolVar(&verbose, "verbose", false, "enable verbose output")
+		cmd.fs.BoolVar(&verbose, "v", false, "enable verbose output")
+
+		var buf bytes.Buffer
+
+		err := usage(cmd, &ExecuteOptions{
+			usageTemplate: DefaultUsageTemplate,
+			outputWriter:  &buf,
+		})
+		tutil.Assert(t, tutil.NilErr(err))
+
+		t.Logf("result:\n%s", buf.String())
+
+		expected := `Usage:
+  add [flags] <name> <url>
+
+Flags:
+  --fetch
+      fetch the remote after adding it
+
+Global Flags:
+  -v, --verbose
+      enable verbose output
+`
+
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Fatalf("usage text mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestLongUsage(t *testing.T) {
+	cmd := command{
+		Command: &BaseCommand{
+			CommandName: "delete",
+			CommandDocumentation: CommandDocumentation{
+				Usage: "delete [flags] <name>",
+				Help:  "Delete resources by name.",
+			},
+		},
+		fs: flag.NewFlagSet("delete", flag.ContinueOnError),
+	}
+
+	var cascade string
+
+	(&getopt.PosixFlagSet{FlagSet: cmd.fs}).VarLong(getopt.Enum(&cascade, "background", "orphan"), "cascade",
+		"deletion cascading strategy", "Must be \"background\" or \"orphan\".\nDefaults to background.")
+
+	t.Run("should render short usage in usage text", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := usage(cmd, &ExecuteOptions{usageTemplate: DefaultUsageTemplate, outputWriter: &buf})
+		tutil.Assert(t, tutil.NilErr(err))
+
+		expected := `Usage:
+  delete [flags] <name>
+
+Flags:
+  --cascade=<background|orphan>
+      deletion cascading strategy
+`
+
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Fatalf("usage text mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should render long usage in help text", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := help(cmd, &ExecuteOptions{helpTemplate: DefaultHelpTemplate, outputWriter: &buf})
+		tutil.Assert(t, tutil.NilErr(err))
+
+		expected := `Delete resources by name.
+
+Usage:
+  delete [flags] <name>
+
+Flags:
+  --cascade=<background|orphan>
+      Must be "background" or "orphan".
+      Defaults to background.
+`
+
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Fatalf("help text mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestRenderUsage(t *testing.T) {
+	var ran bool
+
+	cmd := Tree(
+		New("tool").Usage("tool [command]").Sub(
+			New("remote").Usage("remote [command]").Sub(
+				New("add").Usage("add [flags] <name> <url>").Flags(func(fs *flag.FlagSet) {
+					fs.Bool("fetch", false, "fetch the remote after adding it")
+				}).Init(func(ctx context.Context, args []string) error {
+					ran = true
+					return nil
+				}).Run(func(ctx context.Context, args []string) error {
+					ran = true
+					return nil
+				}),
+			),
+		),
+	)
+
+	t.Run("should render usage for nested subcommand", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RenderUsage(cmd, []string{"remote", "add"}, &buf)
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(false, ran))
+
+		expected := `Usage:
+  add [flags] <name> <url>
+
+Flags:
+  --fetch
+      fetch the remote after adding it
+
+  -h
+      show command usage information
+
+  --help
+      show command help information
+`
+
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Fatalf("usage text mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should render usage for root command with empty path", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RenderUsage(cmd, nil, &buf)
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(buf.String(), "Usage:\n  tool [command]\n")))
+	})
+
+	t.Run("should render command path if usage line is empty", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		cmd := Tree(New("tool").Sub(New("remote").Sub(New("prune"))))
+
+		err := RenderUsage(cmd, []string{"remote", "prune"}, &buf)
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(buf.String(), "Usage:\n  tool remote prune [flags]\n")))
+
+		buf.Reset()
+
+		err = Execute(t.Context(), cmd, WithArgs([]string{"remote", "prune", "-h"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(buf.String(), "Usage:\n  tool remote prune [flags]\n")))
+	})
+
+	t.Run("should return error if path is invalid", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := RenderUsage(cmd, []string{"remote", "rename"}, &buf)
+		tutil.Assert(t, tutil.Eq("cmder: command 'tool remote' has no subcommand 'rename'", err.Error()))
+		tutil.Assert(t, tutil.Eq(0, buf.Len()))
+	})
+}
+
+// templatedCommand is a [Command] implementing [TemplatedCommand].
+type templatedCommand struct {
+	BaseCommand
+
+	template string
+}
+
+// UsageTemplate returns the command usage template.
+func (c templatedCommand) UsageTemplate() string {
+	return c.template
+}
+
+func TestTemplatedCommand(t *testing.T) {
+	tree := func() Command {
+		return &BaseCommand{
+			CommandName: "tool",
+			Children: []Command{
+				&templatedCommand{
+					BaseCommand: BaseCommand{CommandName: "complex"},
+					template:    "custom usage for {{ .Path }}\n",
+				},
+				&BaseCommand{CommandName: "simple"},
+			},
+		}
+	}
+
+	t.Run("should render usage with template of command", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithArgs([]string{"complex", "-h"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq("custom usage for tool complex\n", buf.String()))
+	})
+
+	t.Run("should render usage with default template otherwise", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithArgs([]string{"simple", "-h"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(buf.String(), "Usage:\n  tool simple [flags]\n")))
+	})
+}
+
+func TestAliases(t *testing.T) {
+	tree := func() Command {
+		return Tree(
+			New("tool").Sub(
+				New("generate").Aliases("gen", "g").ShortHelp("generate code"),
+				New("get").ShortHelp("get resources"),
+			),
+		)
+	}
+
+	t.Run("should render aliases of aliased command", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithArgs([]string{"gen", "-h"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq(true,
+			strings.HasPrefix(buf.String(), "Usage:\n  tool generate [flags]\n\nAliases:\n  generate, gen, g\n\n")))
+	})
+
+	t.Run("should not render aliases for command without aliases", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithArgs([]string{"get", "-h"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq(false, strings.Contains(buf.String(), "Aliases:")))
+	})
+
+	t.Run("should not render aliases of subcommands in parent usage", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithArgs([]string{"-h"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq(false, strings.Contains(buf.String(), "Aliases:")))
+	})
+}
+
+func TestDispatcherUsage(t *testing.T) {
+	tree := func() Command {
+		return Tree(
+			New("tool").Help("Manage remote resources.").Sub(
+				New("remote").Help("Manage remotes.").Sub(
+					New("add").ShortHelp("add a remote"),
+					New("remove").ShortHelp("remove a remote"),
+				),
+				New("fetch").Help("Fetch from remotes.").Flags(func(fs *flag.FlagSet) {
+					fs.Bool("all", false, "fetch all remotes")
+				}).Sub(
+					New("tags").ShortHelp("fetch tags"),
+				),
+			),
+		)
+	}
+
+	t.Run("should render compact help for dispatcher commands", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithArgs([]string{"remote", "--help"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowHelp))
+
+		expected := `Manage remotes.
+
+Usage:
+  tool remote [command]
+
+Available Commands:
+  add            add a remote
+  remove         remove a remote
+
+Use "remote [command] --help" for more information about a command.
+`
+
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Fatalf("help text mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should render full help for commands with flags", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithArgs([]string{"fetch", "--help"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowHelp))
+
+		expected := `Fetch from remotes.
+
+Usage:
+  tool fetch [flags]
+
+Available Commands:
+  tags           fetch tags
+
+Flags:
+  --all
+      fetch all remotes
+
+  -h
+      show command usage information
+
+  --help
+      show command help information
+
+Use "fetch [command] --help" for more information about a command.
+`
+
+		if diff := cmp.Diff(expected, buf.String()); diff != "" {
+			t.Fatalf("help text mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("should render full help for commands without subcommands", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithArgs([]string{"remote", "add", "-h"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq(true, strings.HasPrefix(buf.String(), "Usage:\n  tool remote add [flags]\n\nFlags:\n")))
+	})
 }
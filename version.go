@@ -0,0 +1,85 @@
+package cmder
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// versionCommandName is the name of the subcommand injected by [WithVersionCommand].
+const versionCommandName = "version"
+
+// BuildInfo describes the build of a command, rendered by the version subcommand (see [WithVersionCommand]). Empty
+// fields are omitted.
+type BuildInfo struct {
+	// The revision the command was built from (e.g. a commit hash).
+	Commit string
+
+	// The date the command was built.
+	Date string
+}
+
+// VersionCommandOption is an option given to [WithVersionCommand] to adjust the version subcommand.
+type VersionCommandOption func(*ExecuteOptions)
+
+// VersionBuild configures the version subcommand to render build information after the version.
+//
+//	cmder.WithVersionCommand("1.2.0", cmder.VersionBuild(cmder.BuildInfo{Commit: commit, Date: date}))
+func VersionBuild(info BuildInfo) VersionCommandOption {
+	return func(ops *ExecuteOptions) {
+		ops.versionBuild = info
+	}
+}
+
+// versionCommand is the [Command] injected by [WithVersionCommand]. It renders the version of the root command.
+type versionCommand struct {
+	CommandDocumentation
+
+	version string
+	build   BuildInfo
+	output  io.Writer
+}
+
+// newVersionCommand builds the version subcommand for root, writing the version to output.
+func newVersionCommand(root Command, version string, build BuildInfo, output io.Writer) *versionCommand {
+	return &versionCommand{
+		CommandDocumentation: CommandDocumentation{
+			Usage:     fmt.Sprintf("%s version", root.Name()),
+			ShortHelp: "show version information",
+			Help:      fmt.Sprintf("Show the version of %s.", root.Name()),
+		},
+		version: version,
+		build:   build,
+		output:  output,
+	}
+}
+
+// Name returns the name of the version subcommand.
+func (c *versionCommand) Name() string {
+	return versionCommandName
+}
+
+// Run renders the version, followed by any build information.
+func (c *versionCommand) Run(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return ErrShowUsage
+	}
+
+	if _, err := fmt.Fprintln(c.output, c.version); err != nil {
+		return err
+	}
+
+	if c.build.Commit != "" {
+		if _, err := fmt.Fprintf(c.output, "commit: %s\n", c.build.Commit); err != nil {
+			return err
+		}
+	}
+
+	if c.build.Date != "" {
+		if _, err := fmt.Fprintf(c.output, "date: %s\n", c.build.Date); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package cmder
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/brandon1024/cmder/internal/tutil"
+)
+
+func TestVersionCommand(t *testing.T) {
+	tree := func() Command {
+		return Tree(New("root").Sub(New("remote")))
+	}
+
+	t.Run("should render version", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithVersionCommand("1.2.0"), WithArgs([]string{"version"}),
+			WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq("1.2.0\n", buf.String()))
+	})
+
+	t.Run("should render build information", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithVersionCommand("1.2.0", VersionBuild(BuildInfo{
+			Commit: "3f2c9e1",
+			Date:   "2024-05-01",
+		})), WithArgs([]string{"version"}), WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq("1.2.0\ncommit: 3f2c9e1\ndate: 2024-05-01\n", buf.String()))
+	})
+
+	t.Run("should render usage if given arguments", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := Execute(t.Context(), tree(), WithVersionCommand("1.2.0"), WithArgs([]string{"version", "extra"}),
+			WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.IsErr(err, ErrShowUsage))
+		tutil.Assert(t, tutil.Eq(true, bytes.HasPrefix(buf.Bytes(), []byte("Usage:\n  root version"))))
+	})
+
+	t.Run("should not inject version command unless enabled", func(t *testing.T) {
+		var args []string
+
+		cmd := Tree(New("root").Run(func(_ context.Context, a []string) error {
+			args = a
+			return nil
+		}))
+
+		err := Execute(t.Context(), cmd, WithArgs([]string{"version"}))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Match([]string{"version"}, args))
+	})
+
+	t.Run("should not override existing version command", func(t *testing.T) {
+		var (
+			buf bytes.Buffer
+			ran bool
+		)
+
+		cmd := Tree(New("root").Sub(New("ver").Aliases("version").Run(func(context.Context, []string) error {
+			ran = true
+			return nil
+		})))
+
+		err := Execute(t.Context(), cmd, WithVersionCommand("1.2.0"), WithArgs([]string{"version"}),
+			WithOutputWriter(&buf))
+		tutil.Assert(t, tutil.NilErr(err))
+		tutil.Assert(t, tutil.Eq(true, ran))
+		tutil.Assert(t, tutil.Eq("", buf.String()))
+	})
+}